@@ -3,6 +3,7 @@ package ktf
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/blang/semver/v4"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/argocd"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/certmanager"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/gatewayapi"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/httpbin"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/istio"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
@@ -42,6 +44,9 @@ var environmentsCmd = &cobra.Command{
 func init() { //nolint:gochecknoinits
 	environmentsCmd.AddCommand(environmentsCreateCmd)
 
+	// environment spec
+	environmentsCreateCmd.PersistentFlags().StringP("file", "f", "", "path to a YAML environment spec describing the environment (exclusive with other flags)")
+
 	// environment naming
 	environmentsCreateCmd.PersistentFlags().String("name", DefaultEnvironmentName, "name to give the new testing environment")
 	environmentsCreateCmd.PersistentFlags().Bool("generate-name", false, "indicate whether or not to use a generated name for the environment")
@@ -50,6 +55,7 @@ func init() { //nolint:gochecknoinits
 	environmentsCreateCmd.PersistentFlags().String("kubernetes-version", "", "which kubernetes version to use (default: latest for driver)")
 	environmentsCreateCmd.PersistentFlags().Bool("cni-calico", false, "use Calico for cluster CNI instead of the default CNI")
 	environmentsCreateCmd.PersistentFlags().Bool("ipv6-only", false, "only use IPv6")
+	environmentsCreateCmd.PersistentFlags().Bool("dual-stack", false, "use both IPv4 and IPv6")
 
 	// addon configurations
 	environmentsCreateCmd.PersistentFlags().StringArray("addon", nil, "name of an addon to deploy to the testing environment's cluster")
@@ -58,6 +64,8 @@ func init() { //nolint:gochecknoinits
 	environmentsCreateCmd.PersistentFlags().String("kong-ingress-controller-image", "", "use a specific ingress controller container image for the Gateway (proxy)")
 	environmentsCreateCmd.PersistentFlags().String("kong-gateway-image", "", "use a specific container image for the Gateway (proxy)")
 	environmentsCreateCmd.PersistentFlags().String("kong-dbmode", "off", "indicate the backend dbmode to use for kong (default: \"off\" (DBLESS mode))")
+	environmentsCreateCmd.PersistentFlags().StringArray("kong-values", nil, "path to a helm values file for the kong addon (can be repeated, later files take precedence)")
+	environmentsCreateCmd.PersistentFlags().StringArray("kong-custom-plugin", nil, "load a custom Lua plugin into the kong addon from a local directory, as name=path (can be repeated)")
 }
 
 var environmentsCreateCmd = &cobra.Command{
@@ -67,60 +75,23 @@ var environmentsCreateCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), EnvironmentCreateTimeout)
 		defer cancel()
 
-		// get the name for the environment (if not provided, a uuid will be generated)
-		name, err := cmd.PersistentFlags().GetString("name")
-		cobra.CheckErr(err)
-
-		// get any addons for the cluster that were desired
-		deployAddons, err := cmd.PersistentFlags().GetStringArray("addon")
+		// check if the environment is described by a spec file
+		specFile, err := cmd.PersistentFlags().GetString("file")
 		cobra.CheckErr(err)
 
-		// verify whether the environment was flagged to use a generated name
-		useGeneratedName, err := cmd.PersistentFlags().GetBool("generate-name")
-		cobra.CheckErr(err)
-
-		// check if a specific Kubernetes version was requested
-		kubernetesVersion, err := cmd.PersistentFlags().GetString("kubernetes-version")
-		cobra.CheckErr(err)
-
-		// check if Calico CNI was requested
-		useCalicoCNI, err := cmd.PersistentFlags().GetBool("cni-calico")
-		cobra.CheckErr(err)
-
-		// check if IPv6 was requested
-		useIPv6Only, err := cmd.PersistentFlags().GetBool("ipv6-only")
-		cobra.CheckErr(err)
-
-		// setup the new environment
-		builder := environments.NewBuilder()
-		if !useGeneratedName {
-			builder = builder.WithName(name)
-		}
-		if useCalicoCNI {
-			builder = builder.WithCalicoCNI()
-		}
-		if useIPv6Only {
-			builder = builder.WithIPv6Only()
-		}
-		if kubernetesVersion != "" {
-			version, err := semver.Parse(strings.TrimPrefix(kubernetesVersion, "v"))
-			cobra.CheckErr(err)
-			builder = builder.WithKubernetesVersion(version)
+		var builder *environments.Builder
+		var callbacks []func()
+		if specFile != "" {
+			builder, callbacks = configureFromSpecFile(cmd, specFile)
+		} else {
+			builder, callbacks = configureFromFlags(cmd)
 		}
 
-		// configure any addons that need to be deployed with the environment's cluster
-		callbacks := configureAddons(cmd, builder, deployAddons)
+		// report the progress of the environment as it's built
+		builder = builder.WithObserver(environments.NewPrintObserver(os.Stdout))
 
-		fmt.Printf("building new environment %s\n", builder.Name)
 		env, err := builder.Build(ctx)
 		cobra.CheckErr(err)
-
-		addons := env.Cluster().ListAddons()
-		for _, addon := range addons {
-			fmt.Printf("waiting for addon %s to become ready...\n", addon.Name())
-		}
-
-		fmt.Println("waiting for environment to become ready (this can take some time)...")
 		cobra.CheckErr(<-env.WaitForReady(ctx))
 
 		fmt.Printf("environment %s was created successfully!\n", env.Name())
@@ -130,6 +101,84 @@ var environmentsCreateCmd = &cobra.Command{
 	},
 }
 
+func configureFromSpecFile(cmd *cobra.Command, specFile string) (*environments.Builder, []func()) {
+	// the spec describes the whole environment, so any other flags would be ignored
+	if cmd.PersistentFlags().NFlag() > 1 {
+		cobra.CheckErr(fmt.Errorf("--file can't be combined with other flags, configure the environment in the spec instead"))
+	}
+
+	spec, err := environments.LoadSpecFile(specFile)
+	cobra.CheckErr(err)
+
+	builder, err := environments.NewBuilderFromSpec(spec)
+	cobra.CheckErr(err)
+
+	// the same help is provided for addons as when they're configured with flags
+	callbacks := make([]func(), 0)
+	for _, addon := range spec.Addons {
+		if addon.Name == registry.AddonName {
+			callbacks = append(callbacks, registryHelpCallback(registry.Namespace))
+		}
+	}
+
+	return builder, callbacks
+}
+
+func configureFromFlags(cmd *cobra.Command) (*environments.Builder, []func()) {
+	// get the name for the environment (if not provided, a uuid will be generated)
+	name, err := cmd.PersistentFlags().GetString("name")
+	cobra.CheckErr(err)
+
+	// get any addons for the cluster that were desired
+	deployAddons, err := cmd.PersistentFlags().GetStringArray("addon")
+	cobra.CheckErr(err)
+
+	// verify whether the environment was flagged to use a generated name
+	useGeneratedName, err := cmd.PersistentFlags().GetBool("generate-name")
+	cobra.CheckErr(err)
+
+	// check if a specific Kubernetes version was requested
+	kubernetesVersion, err := cmd.PersistentFlags().GetString("kubernetes-version")
+	cobra.CheckErr(err)
+
+	// check if Calico CNI was requested
+	useCalicoCNI, err := cmd.PersistentFlags().GetBool("cni-calico")
+	cobra.CheckErr(err)
+
+	// check if IPv6 was requested
+	useIPv6Only, err := cmd.PersistentFlags().GetBool("ipv6-only")
+	cobra.CheckErr(err)
+
+	// check if dual-stack was requested
+	useDualStack, err := cmd.PersistentFlags().GetBool("dual-stack")
+	cobra.CheckErr(err)
+
+	// setup the new environment
+	builder := environments.NewBuilder()
+	if !useGeneratedName {
+		builder = builder.WithName(name)
+	}
+	if useCalicoCNI {
+		builder = builder.WithCalicoCNI()
+	}
+	if useIPv6Only {
+		builder = builder.WithIPv6Only()
+	}
+	if useDualStack {
+		builder = builder.WithDualStack()
+	}
+	if kubernetesVersion != "" {
+		version, err := semver.Parse(strings.TrimPrefix(kubernetesVersion, "v"))
+		cobra.CheckErr(err)
+		builder = builder.WithKubernetesVersion(version)
+	}
+
+	// configure any addons that need to be deployed with the environment's cluster
+	callbacks := configureAddons(cmd, builder, deployAddons)
+
+	return builder, callbacks
+}
+
 func configureAddons(cmd *cobra.Command, builder *environments.Builder, addons []string) []func() {
 	invalid, dedup := make([]string, 0), make(map[string]bool)
 	// sometimes some addons which are configured for need to do something AFTER
@@ -158,6 +207,8 @@ func configureAddons(cmd *cobra.Command, builder *environments.Builder, addons [
 			builder = builder.WithAddons(certmanager.New())
 		case "kuma":
 			builder = builder.WithAddons(kuma.New())
+		case "gateway-api":
+			builder = builder.WithAddons(gatewayapi.New())
 		case "argocd":
 			argoAddon := argocd.NewBuilder().Build()
 			builder = builder.WithAddons(argoAddon)
@@ -169,8 +220,30 @@ func configureAddons(cmd *cobra.Command, builder *environments.Builder, addons [
 				WithServiceTypeLoadBalancer().
 				Build()
 			builder = builder.WithAddons(registryAddon)
-			registryInfoCallback := func() {
-				fmt.Printf(`
+			callbacks = append(callbacks, registryHelpCallback(registryAddon.Namespace()))
+		default:
+			invalid = append(invalid, addon)
+		}
+
+		// fail if any duplicate addons were provided
+		if _, ok := dedup[addon]; ok {
+			cobra.CheckErr(fmt.Errorf("addon %s was provided more than once", addon))
+		}
+		dedup[addon] = true
+	}
+
+	if len(invalid) > 0 {
+		cobra.CheckErr(fmt.Errorf("%d addons were invalid: %s", len(invalid), invalid))
+	}
+
+	return callbacks
+}
+
+// registryHelpCallback provides a callback which explains how to push images
+// to the registry addon deployed to the namespace.
+func registryHelpCallback(namespace string) func() {
+	return func() {
+		fmt.Printf(`
 Registry Addon HELP:
 
 You have installed the registry addon deployed with an SSL certificate provided
@@ -201,25 +274,8 @@ Afterwards you should be able to push images to the registry, e.g.:
 
 Images pushed this way should be immediately usable in pod configurations
 on the cluster as the certificate is automatically configured on the nodes.
-`, registryAddon.Namespace(), registryAddon.Namespace())
-			}
-			callbacks = append(callbacks, registryInfoCallback)
-		default:
-			invalid = append(invalid, addon)
-		}
-
-		// fail if any duplicate addons were provided
-		if _, ok := dedup[addon]; ok {
-			cobra.CheckErr(fmt.Errorf("addon %s was provided more than once", addon))
-		}
-		dedup[addon] = true
+`, namespace, namespace)
 	}
-
-	if len(invalid) > 0 {
-		cobra.CheckErr(fmt.Errorf("%d addons were invalid: %s", len(invalid), invalid))
-	}
-
-	return callbacks
 }
 
 func configureKongAddon(cmd *cobra.Command, envBuilder *environments.Builder) *environments.Builder {
@@ -279,6 +335,33 @@ func configureKongAddon(cmd *cobra.Command, envBuilder *environments.Builder) *e
 		cobra.CheckErr(fmt.Errorf("%s is not a valid dbmode for kong, supported modes are \"off\" (DBLESS) or \"postgres\"", dbmode))
 	}
 
+	valuesFiles, err := cmd.PersistentFlags().GetStringArray("kong-values")
+	cobra.CheckErr(err)
+
+	for _, valuesFile := range valuesFiles {
+		// fail before the cluster is created if the file can't be read
+		_, err := os.Stat(valuesFile)
+		cobra.CheckErr(err)
+		builder.WithValuesFile(valuesFile)
+	}
+
+	customPlugins, err := cmd.PersistentFlags().GetStringArray("kong-custom-plugin")
+	cobra.CheckErr(err)
+
+	for _, customPlugin := range customPlugins {
+		name, sourceDir, ok := strings.Cut(customPlugin, "=")
+		if !ok || name == "" || sourceDir == "" {
+			cobra.CheckErr(fmt.Errorf("malformed --kong-custom-plugin: %s (expected name=path)", customPlugin))
+		}
+		// fail before the cluster is created if the directory can't be read
+		info, err := os.Stat(sourceDir)
+		cobra.CheckErr(err)
+		if !info.IsDir() {
+			cobra.CheckErr(fmt.Errorf("--kong-custom-plugin %s: %s is not a directory", name, sourceDir))
+		}
+		builder.WithCustomPlugin(name, sourceDir)
+	}
+
 	return envBuilder.WithAddons(builder.Build())
 }
 
@@ -0,0 +1,23 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Do runs fn until it succeeds, retrying it like commands are retried.
+func Do(ctx context.Context, fn func() error) error {
+	return retry.Do(fn,
+		retry.Context(ctx),
+		retry.Delay(retryWait),
+		retry.Attempts(retryCount),
+		retry.DelayType(retry.FixedDelay),
+		retry.OnRetry(func(_ uint, err error) {
+			if err != nil {
+				logrus.WithError(err).Error("failed, retrying")
+			}
+		}),
+	)
+}
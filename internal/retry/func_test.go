@@ -0,0 +1,24 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/internal/retry"
+)
+
+func TestDoFunc(t *testing.T) {
+	calls := 0
+	err := retry.Do(context.Background(), func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls, "expected the function to be retried once after it failed")
+}
@@ -16,7 +16,7 @@ import (
 const (
 	// AdminNamespace is the namespace used for administrative acts
 	// by KTF for purposes such as deploying addons.
-	AdminNamespace = "ktf-system"
+	AdminNamespace = clusters.AdminNamespace
 
 	// AdminBinding is the name of the ClusterRoleBinding created to
 	// bind the AdminNamespace's default service account to the
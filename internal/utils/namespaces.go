@@ -17,8 +17,10 @@ import (
 // of seconds).
 //
 // If the namespace is not yet available a list of the components being waited
-// on will be provided.
-func IsNamespaceAvailable(ctx context.Context, cluster clusters.Cluster, namespace string) (waitForObjects []runtime.Object, available bool, err error) {
+// on will be provided. If any of them failed unrecoverably (according to the
+// context's clusters.FailurePolicy) a *clusters.ErrAddonFailed is returned for
+// the given addon.
+func IsNamespaceAvailable(ctx context.Context, cluster clusters.Cluster, addon clusters.AddonName, namespace string) (waitForObjects []runtime.Object, available bool, err error) {
 	// if the namespace itself isn't available yet, not ready
 	_, err = cluster.Client().CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
 	if err != nil {
@@ -71,6 +73,11 @@ func IsNamespaceAvailable(ctx context.Context, cluster clusters.Cluster, namespa
 		}
 	}
 
+	// fail fast if any of the components we're waiting on won't recover
+	if err = clusters.CheckForFailures(ctx, cluster, addon, waitForObjects); err != nil {
+		return
+	}
+
 	// if there are no daemonsets or deployments present we can't consider this ready yet
 	// the expectation is that at least one (of any type) exists.
 	if (len(daemonsets.Items) + len(deployments.Items)) == 0 {
@@ -0,0 +1,215 @@
+package clusters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// -----------------------------------------------------------------------------
+// Public Consts & Vars - Addon State
+// -----------------------------------------------------------------------------
+
+const (
+	// AdminNamespace is the namespace used for administrative acts by KTF, such
+	// as deploying addons and recording which addons have been deployed.
+	AdminNamespace = "ktf-system"
+
+	// AddonStateConfigMapName is the name of the ConfigMap in the AdminNamespace
+	// which records the addons that have been deployed to a cluster.
+	AddonStateConfigMapName = "ktf-addons"
+)
+
+// -----------------------------------------------------------------------------
+// Public Types - Addon State
+// -----------------------------------------------------------------------------
+
+// AddonState is the record of a deployed Addon which is stored in the cluster
+// so that other processes attaching to the cluster can rehydrate the Addon.
+type AddonState struct {
+	// Name is the name of the deployed Addon.
+	Name AddonName `json:"name"`
+
+	// Type identifies the registered AddonRehydrateFunc which can rebuild the
+	// Addon. It's empty for addons which can't be rehydrated.
+	Type AddonName `json:"type,omitempty"`
+
+	// Version is the version of the deployed component, if any.
+	Version string `json:"version,omitempty"`
+
+	// Options are the addon specific builder options the Addon was built with.
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// NewAddonState provides the AddonState for an Addon, encoding its options
+// (if any).
+func NewAddonState(name, addonType AddonName, version string, options any) (AddonState, error) {
+	state := AddonState{
+		Name:    name,
+		Type:    addonType,
+		Version: version,
+	}
+	if options == nil {
+		return state, nil
+	}
+
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return AddonState{}, fmt.Errorf("failed to encode options of addon %s: %w", name, err)
+	}
+	state.Options = encoded
+	return state, nil
+}
+
+// DecodeOptions decodes the addon specific builder options into the given value.
+func (s AddonState) DecodeOptions(options any) error {
+	if len(s.Options) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(s.Options, options); err != nil {
+		return fmt.Errorf("failed to decode options of addon %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// StatefulAddon is an optional interface for Addons which can describe their
+// state so that they can be rehydrated by other processes.
+type StatefulAddon interface {
+	Addon
+
+	// State provides the type, version and builder options of the Addon.
+	// Secrets (e.g. passwords and licenses) must not be included.
+	State() (AddonState, error)
+}
+
+// AddonRehydrateFunc rebuilds an Addon given its recorded state.
+type AddonRehydrateFunc func(state AddonState) (Addon, error)
+
+// -----------------------------------------------------------------------------
+// Public Functions - Addon State
+// -----------------------------------------------------------------------------
+
+// RegisterAddonType registers the function which can rehydrate Addons of the
+// given type. Addon packages register themselves when they are imported.
+func RegisterAddonType(addonType AddonName, rehydrate AddonRehydrateFunc) {
+	addonTypesLock.Lock()
+	defer addonTypesLock.Unlock()
+	addonTypes[addonType] = rehydrate
+}
+
+// SaveAddonState records the state of a deployed Addon in the cluster. Addons
+// which don't implement StatefulAddon are recorded by name only.
+func SaveAddonState(ctx context.Context, cluster Cluster, addon Addon) error {
+	state := AddonState{Name: addon.Name()}
+	if statefulAddon, ok := addon.(StatefulAddon); ok {
+		var err error
+		if state, err = statefulAddon.State(); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode state of addon %s: %w", addon.Name(), err)
+	}
+
+	return updateAddonStateConfigMap(ctx, cluster.Client(), func(data map[string]string) {
+		data[string(addon.Name())] = string(encoded)
+	})
+}
+
+// DeleteAddonState removes the recorded state of an Addon from the cluster.
+func DeleteAddonState(ctx context.Context, cluster Cluster, name AddonName) error {
+	return updateAddonStateConfigMap(ctx, cluster.Client(), func(data map[string]string) {
+		delete(data, string(name))
+	})
+}
+
+// LoadAddons rehydrates the Addons recorded in the cluster. Addons whose type
+// was not registered (e.g. because their package was not imported) are skipped.
+func LoadAddons(ctx context.Context, c kubernetes.Interface) (Addons, error) {
+	addons := make(Addons)
+
+	configMap, err := c.CoreV1().ConfigMaps(AdminNamespace).Get(ctx, AddonStateConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return addons, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve addon state: %w", err)
+	}
+
+	addonTypesLock.RLock()
+	defer addonTypesLock.RUnlock()
+
+	for key, value := range configMap.Data {
+		var state AddonState
+		if err := json.Unmarshal([]byte(value), &state); err != nil {
+			return nil, fmt.Errorf("failed to decode state of addon %s: %w", key, err)
+		}
+		rehydrate, ok := addonTypes[state.Type]
+		if !ok {
+			continue
+		}
+		addon, err := rehydrate(state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rehydrate addon %s: %w", state.Name, err)
+		}
+		addons[addon.Name()] = addon
+	}
+
+	return addons, nil
+}
+
+// -----------------------------------------------------------------------------
+// Private - Addon State
+// -----------------------------------------------------------------------------
+
+var (
+	addonTypesLock sync.RWMutex
+	addonTypes     = make(map[AddonName]AddonRehydrateFunc)
+)
+
+// updateAddonStateConfigMap applies the given update to the data of the addon
+// state ConfigMap, creating it (and the AdminNamespace) if needed. Addons are
+// often deployed concurrently, so conflicting updates are retried.
+func updateAddonStateConfigMap(ctx context.Context, c kubernetes.Interface, update func(data map[string]string)) error {
+	configMaps := c.CoreV1().ConfigMaps(AdminNamespace)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMaps.Get(ctx, AddonStateConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: AdminNamespace}}
+			if _, err := c.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+
+			configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: AddonStateConfigMapName, Namespace: AdminNamespace}}
+			configMap.Data = make(map[string]string)
+			update(configMap.Data)
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			if errors.IsAlreadyExists(err) {
+				// another addon was recorded at the same time, retry as an update.
+				return errors.NewConflict(corev1.Resource("configmaps"), AddonStateConfigMapName, err)
+			}
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		update(configMap.Data)
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
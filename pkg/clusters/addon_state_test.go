@@ -0,0 +1,68 @@
+package clusters_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestAddonStateIsRecordedAndRemoved(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+	addon := fake.NewAddon("test")
+
+	t.Log("verifying that deployed addons are recorded in the addon state ConfigMap")
+	require.NoError(t, cluster.DeployAddon(ctx, addon))
+	configMap, err := cluster.Client().CoreV1().ConfigMaps(clusters.AdminNamespace).Get(ctx, clusters.AddonStateConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"test"}`, configMap.Data["test"])
+
+	t.Log("verifying that addons which can't be rehydrated are not loaded")
+	addons, err := clusters.LoadAddons(ctx, cluster.Client())
+	require.NoError(t, err)
+	assert.Empty(t, addons)
+
+	t.Log("verifying that deleted addons are removed from the addon state ConfigMap")
+	require.NoError(t, cluster.DeleteAddon(ctx, addon))
+	configMap, err = cluster.Client().CoreV1().ConfigMaps(clusters.AdminNamespace).Get(ctx, clusters.AddonStateConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, configMap.Data, "test")
+}
+
+func TestAddonStateRehydratesRegisteredAddons(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+	addon := kong.NewBuilder().
+		WithName("kong-test").
+		WithNamespace("kong-test").
+		WithDBLess().
+		WithProxyImage("kong", "3.9").
+		WithProxyEnvVar("router_flavor", "expressions").
+		WithHelmChartVersion("2.48.0").
+		Build()
+
+	t.Log("recording the state of a kong addon")
+	require.NoError(t, clusters.SaveAddonState(ctx, cluster, addon))
+
+	t.Log("verifying that the kong addon is rehydrated with the same options")
+	addons, err := clusters.LoadAddons(ctx, cluster.Client())
+	require.NoError(t, err)
+	require.Contains(t, addons, clusters.AddonName("kong-test"))
+	rehydrated, ok := addons["kong-test"].(*kong.Addon)
+	require.True(t, ok)
+	assert.Equal(t, "kong-test", rehydrated.Namespace())
+
+	want, err := addon.State()
+	require.NoError(t, err)
+	got, err := rehydrated.State()
+	require.NoError(t, err)
+	assert.Equal(t, want.Version, got.Version)
+	assert.JSONEq(t, string(want.Options), string(got.Options))
+}
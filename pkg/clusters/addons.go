@@ -2,6 +2,8 @@ package clusters
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -26,8 +28,9 @@ type Addon interface {
 	Dependencies(ctx context.Context, cluster Cluster) []AddonName
 
 	// Deploy deploys the addon component to a provided cluster.
-	// Addon implementations are responsible for waiting for their
-	// own dependencies to deploy as needed.
+	// Environments only deploy an addon once its dependencies are ready, but
+	// addon implementations are still responsible for waiting for their own
+	// dependencies (see WaitForAddonDependencies) when deployed directly.
 	Deploy(ctx context.Context, cluster Cluster) error
 
 	// Delete removes the addon component from the given cluster.
@@ -43,3 +46,57 @@ type Addon interface {
 	// If the addon has failed unrecoverably, it will provide an error.
 	Ready(ctx context.Context, cluster Cluster) (waitingForObjects []runtime.Object, ready bool, err error)
 }
+
+// UpgradableAddon is an Addon which can be upgraded in place, e.g. to a new
+// version, without being deleted and deployed again.
+type UpgradableAddon interface {
+	Addon
+
+	// Upgrade upgrades the deployed addon to the configuration of the target
+	// addon, which is expected to be of the same type. Once upgraded the addon
+	// reflects the target's configuration.
+	Upgrade(ctx context.Context, cluster Cluster, target Addon) error
+}
+
+// -----------------------------------------------------------------------------
+// Public Functions - Cluster Addons
+// -----------------------------------------------------------------------------
+
+// ErrAddonNotUpgradable indicates that a deployed addon can't be upgraded in
+// place because it doesn't implement UpgradableAddon.
+var ErrAddonNotUpgradable = errors.New("addon can't be upgraded")
+
+// UpgradeAddon upgrades a deployed addon (see Cluster.GetAddon) in place to
+// the configuration of the target addon of the same name, and records its new
+// state in the cluster. The deployed addon must be an UpgradableAddon. It's not
+// a Cluster method so that extending the interface doesn't break its
+// implementations outside of this module.
+func UpgradeAddon(ctx context.Context, cluster Cluster, deployed Addon, target Addon) error {
+	if deployed.Name() != target.Name() {
+		return fmt.Errorf("can't upgrade addon %s to addon %s", deployed.Name(), target.Name())
+	}
+	upgradable, ok := deployed.(UpgradableAddon)
+	if !ok {
+		return fmt.Errorf("%s: %w", deployed.Name(), ErrAddonNotUpgradable)
+	}
+
+	if err := upgradable.Upgrade(ctx, cluster, target); err != nil {
+		return fmt.Errorf("failed to upgrade addon %s: %w", deployed.Name(), err)
+	}
+
+	return SaveAddonState(ctx, cluster, deployed)
+}
+
+// ErrUnsupportedClusterType indicates that an addon can't be deployed to the
+// type of cluster it was given.
+var ErrUnsupportedClusterType = errors.New("unsupported on this cluster type")
+
+// RequireWorkloads returns an error wrapping ErrUnsupportedClusterType if the
+// given cluster can't run the workloads (e.g. Pods) which the addon needs.
+// Addons which deploy workloads should call this before deploying anything.
+func RequireWorkloads(cluster Cluster, addon Addon) error {
+	if ws, ok := cluster.(WorkloadSupporter); ok && !ws.SupportsWorkloads() {
+		return fmt.Errorf("addon %s needs workloads which are %w (%s)", addon.Name(), ErrUnsupportedClusterType, cluster.Type())
+	}
+	return nil
+}
@@ -59,15 +59,21 @@ func applicationGVR() schema.GroupVersionResource {
 
 // CreateApplication takes an (unstructured) Application and creates it.
 func (a *Addon) CreateApplication(ctx context.Context, app *unstructured.Unstructured) error {
-	applicationClient := a.client.Resource(applicationGVR()).Namespace(a.namespace)
+	applicationClient, err := a.resourceClient(applicationGVR())
+	if err != nil {
+		return err
+	}
 
-	_, err := applicationClient.Create(ctx, app, metav1.CreateOptions{})
+	_, err = applicationClient.Create(ctx, app, metav1.CreateOptions{})
 	return err
 }
 
 // DeleteApplication takes an Application name and deletes it.
 func (a *Addon) DeleteApplication(ctx context.Context, proj string) error {
-	client := a.client.Resource(applicationGVR()).Namespace(a.namespace)
+	client, err := a.resourceClient(applicationGVR())
+	if err != nil {
+		return err
+	}
 	return client.Delete(ctx, proj, metav1.DeleteOptions{})
 }
 
@@ -81,17 +87,33 @@ func appProjectGVR() schema.GroupVersionResource {
 
 // CreateAppProject takes an (unstructured) AppProject and creates it.
 func (a *Addon) CreateAppProject(ctx context.Context, proj *unstructured.Unstructured) error {
-	projectClient := a.client.Resource(appProjectGVR()).Namespace(a.namespace)
-	_, err := projectClient.Create(ctx, proj, metav1.CreateOptions{})
+	projectClient, err := a.resourceClient(appProjectGVR())
+	if err != nil {
+		return err
+	}
+	_, err = projectClient.Create(ctx, proj, metav1.CreateOptions{})
 	return err
 }
 
 // DeleteAppProject takes an AppProject name and deletes it.
 func (a *Addon) DeleteAppProject(ctx context.Context, proj string) error {
-	projectClient := a.client.Resource(appProjectGVR()).Namespace(a.namespace)
+	projectClient, err := a.resourceClient(appProjectGVR())
+	if err != nil {
+		return err
+	}
 	return projectClient.Delete(ctx, proj, metav1.DeleteOptions{})
 }
 
+// resourceClient provides a client for the given resource in the addon's
+// namespace. The client is only available once the addon was deployed by
+// this process, addons loaded from an existing cluster don't have one.
+func (a *Addon) resourceClient(gvr schema.GroupVersionResource) (dynamic.ResourceInterface, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("ArgoCD client is not available: addon %s was not deployed by this process", AddonName)
+	}
+	return a.client.Resource(gvr).Namespace(a.namespace), nil
+}
+
 // -----------------------------------------------------------------------------
 // ArgoCD Addon - Addon Implementation
 // -----------------------------------------------------------------------------
@@ -105,6 +127,10 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: a.namespace}}
 	if _, err := cluster.Client().CoreV1().Namespaces().Create(ctx, &ns, metav1.CreateOptions{}); err != nil {
 		if !errors.IsAlreadyExists(err) {
@@ -165,7 +191,8 @@ func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.
 	}
 
 	if deployment.Status.AvailableReplicas != *deployment.Spec.Replicas {
-		return []runtime.Object{deployment}, false, nil
+		waitForObjects := []runtime.Object{deployment}
+		return waitForObjects, false, clusters.CheckForFailures(ctx, cluster, a.Name(), waitForObjects)
 	}
 
 	return nil, true, nil
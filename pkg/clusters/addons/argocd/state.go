@@ -0,0 +1,38 @@
+package argocd
+
+import (
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// ArgoCD Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the cluster.
+type addonState struct {
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// State provides the state of the addon, its version is the ArgoCD release.
+func (a *Addon) State() (clusters.AddonState, error) {
+	return clusters.NewAddonState(a.Name(), AddonName, a.version, addonState{
+		Namespace: a.namespace,
+	})
+}
+
+// rehydrate rebuilds the addon without an ArgoCD client, see resourceClient().
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+	return &Addon{
+		name:      string(s.Name),
+		namespace: opts.Namespace,
+		version:   s.Version,
+	}, nil
+}
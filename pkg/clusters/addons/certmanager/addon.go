@@ -80,6 +80,10 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	var err error
 	if a.version == nil {
 		a.version, err = github.FindLatestReleaseForRepo(ctx, "jetstack", "cert-manager")
@@ -171,7 +175,7 @@ func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
 
 func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.Object, bool, error) {
 	// wait for all the deployments, daemonsets in the namespace
-	waitForObjects, ready, err := utils.IsNamespaceAvailable(ctx, cluster, DefaultNamespace)
+	waitForObjects, ready, err := utils.IsNamespaceAvailable(ctx, cluster, a.Name(), DefaultNamespace)
 	if !ready || err != nil {
 		return waitForObjects, ready, err
 	}
@@ -187,7 +191,8 @@ func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.
 		return []runtime.Object{job}, false, err
 	}
 	if job.Status.Succeeded < 1 {
-		return []runtime.Object{job}, false, nil // not quite ready yet
+		waitForObjects := []runtime.Object{job}
+		return waitForObjects, false, clusters.CheckForFailures(ctx, cluster, a.Name(), waitForObjects) // not quite ready yet
 	}
 
 	return nil, true, nil
@@ -0,0 +1,36 @@
+package certmanager
+
+import (
+	"github.com/blang/semver/v4"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// CertManager Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// State provides the state of the addon, its version is the cert-manager release.
+func (a *Addon) State() (clusters.AddonState, error) {
+	var version string
+	if a.version != nil {
+		version = a.version.String()
+	}
+	return clusters.NewAddonState(a.Name(), AddonName, version, nil)
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	a := &Addon{}
+	if s.Version != "" {
+		version, err := semver.Parse(s.Version)
+		if err != nil {
+			return nil, err
+		}
+		a.version = &version
+	}
+	return a, nil
+}
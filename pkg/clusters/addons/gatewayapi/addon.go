@@ -0,0 +1,187 @@
+// Package gatewayapi provides an addon which installs the Gateway API CRDs
+// (https://gateway-api.sigs.k8s.io/) of a release channel.
+package gatewayapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blang/semver/v4"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/internal/retry"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/github"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway API Addon
+// -----------------------------------------------------------------------------
+
+const (
+	// AddonName indicates the unique name of this addon.
+	AddonName clusters.AddonName = "gateway-api"
+
+	// Group is the API group of the Gateway API resources.
+	Group = "gateway.networking.k8s.io"
+)
+
+// Channel is a release channel of the Gateway API.
+type Channel string
+
+const (
+	// StandardChannel includes the resources which have graduated to GA or
+	// beta, such as GatewayClass, Gateway and HTTPRoute.
+	StandardChannel Channel = "standard"
+
+	// ExperimentalChannel additionally includes the experimental resources
+	// and fields, such as TCPRoute, UDPRoute and TLSRoute.
+	ExperimentalChannel Channel = "experimental"
+)
+
+// Addon installs the Gateway API CRDs. As it only installs CRDs (and their
+// validation policies) it can be deployed to clusters without workloads.
+type Addon struct {
+	version *semver.Version
+	channel Channel
+}
+
+// New produces a new clusters.Addon which installs the CRDs of the latest
+// release of the standard channel.
+func New() *Addon {
+	return NewBuilder().Build()
+}
+
+// Version provides the Gateway API release whose CRDs are installed, or nil if
+// the latest release is installed and the addon wasn't deployed yet.
+func (a *Addon) Version() *semver.Version {
+	return a.version
+}
+
+// Channel provides the release channel whose CRDs are installed.
+func (a *Addon) Channel() Channel {
+	return a.channel
+}
+
+// -----------------------------------------------------------------------------
+// Gateway API Addon - Addon Implementation
+// -----------------------------------------------------------------------------
+
+func (a *Addon) Name() clusters.AddonName {
+	return AddonName
+}
+
+func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.AddonName {
+	return nil
+}
+
+func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if a.version == nil {
+		version, err := github.FindLatestReleaseForRepo(ctx, "kubernetes-sigs", "gateway-api")
+		if err != nil {
+			return err
+		}
+		a.version = version
+	}
+	return a.kubectl(ctx, cluster, "apply", "--server-side", "-f")
+}
+
+// Upgrade installs the CRDs of the target's release and channel. Resources of
+// CRDs which the target's channel doesn't include are left in place.
+func (a *Addon) Upgrade(ctx context.Context, cluster clusters.Cluster, target clusters.Addon) error {
+	t, ok := target.(*Addon)
+	if !ok {
+		return fmt.Errorf("can't upgrade the gateway-api addon to a %T", target)
+	}
+	if err := t.Deploy(ctx, cluster); err != nil {
+		return err
+	}
+	*a = *t
+	return nil
+}
+
+// Delete deletes the CRDs, and with them all the Gateway API resources.
+func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
+	if a.version == nil {
+		return nil // never deployed
+	}
+	return a.kubectl(ctx, cluster, "delete", "--ignore-not-found", "-f")
+}
+
+// Ready indicates whether the CRDs of the Gateway API group are established.
+func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.Object, bool, error) {
+	client, err := apiextensionsclient.NewForConfig(cluster.Config())
+	if err != nil {
+		return nil, false, err
+	}
+	crds, err := client.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	var found bool
+	var waitingForObjects []runtime.Object
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if crd.Spec.Group != Group {
+			continue
+		}
+		found = true
+		if !established(crd) {
+			waitingForObjects = append(waitingForObjects, crd)
+		}
+	}
+	if !found {
+		return nil, false, nil
+	}
+	return waitingForObjects, len(waitingForObjects) == 0, nil
+}
+
+func (a *Addon) DumpDiagnostics(context.Context, clusters.Cluster) (map[string][]byte, error) {
+	diagnostics := make(map[string][]byte)
+	return diagnostics, nil
+}
+
+// -----------------------------------------------------------------------------
+// Gateway API Addon - Private
+// -----------------------------------------------------------------------------
+
+const manifestFormatter = "https://github.com/kubernetes-sigs/gateway-api/releases/download/v%s/%s-install.yaml"
+
+// manifestURL provides the URL of the release manifest of the addon's version
+// and channel.
+func (a *Addon) manifestURL() string {
+	return fmt.Sprintf(manifestFormatter, a.version, a.channel)
+}
+
+// kubectl runs a kubectl subcommand for the release manifest, which is passed
+// as the last argument. Server-side applies are used because the annotation
+// of client-side applies exceeds the size limit for some of the CRDs.
+func (a *Addon) kubectl(ctx context.Context, cluster clusters.Cluster, args ...string) error {
+	// generate a temporary kubeconfig since we use kubectl to deploy this addon
+	kubeconfig, err := clusters.TempKubeconfig(cluster)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(kubeconfig.Name())
+
+	args = append([]string{"--kubeconfig", kubeconfig.Name()}, args...)
+	args = append(args, a.manifestURL())
+	if err := retry.Command("kubectl", args...).Do(ctx); err != nil {
+		return fmt.Errorf("failed to %s gateway API %s channel CRDs %s: %w", args[2], a.channel, a.version, err)
+	}
+	return nil
+}
+
+func established(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
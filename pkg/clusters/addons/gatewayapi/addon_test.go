@@ -0,0 +1,28 @@
+package gatewayapi
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddon(t *testing.T) {
+	t.Log("verifying that the latest standard channel release is installed by default")
+	addon := New()
+	assert.Nil(t, addon.Version())
+	assert.Equal(t, StandardChannel, addon.Channel())
+
+	t.Log("verifying the release manifest of the version and channel")
+	addon = NewBuilder().WithVersion(semver.MustParse("1.2.1")).WithChannel(ExperimentalChannel).Build()
+	assert.Equal(t, "https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.2.1/experimental-install.yaml", addon.manifestURL())
+
+	t.Log("verifying that the version and channel are recorded in the addon state")
+	state, err := addon.State()
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.1", state.Version)
+	rehydrated, err := rehydrate(state)
+	require.NoError(t, err)
+	assert.Equal(t, addon, rehydrated)
+}
@@ -0,0 +1,42 @@
+package gatewayapi
+
+import (
+	"github.com/blang/semver/v4"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway API Addon - Builder
+// -----------------------------------------------------------------------------
+
+// Builder is a configuration tool for Gateway API cluster.Addons.
+type Builder struct {
+	version *semver.Version
+	channel Channel
+}
+
+// NewBuilder provides a new Builder object with default addon settings: the
+// latest release of the standard channel.
+func NewBuilder() *Builder {
+	return &Builder{channel: StandardChannel}
+}
+
+// WithVersion sets the Gateway API release whose CRDs are installed, e.g.
+// 1.2.1. The latest release is installed by default.
+func (b *Builder) WithVersion(version semver.Version) *Builder {
+	b.version = &version
+	return b
+}
+
+// WithChannel sets the release channel whose CRDs are installed.
+func (b *Builder) WithChannel(channel Channel) *Builder {
+	b.channel = channel
+	return b
+}
+
+// Build generates an addon with the builder's configuration.
+func (b *Builder) Build() *Addon {
+	return &Addon{
+		version: b.version,
+		channel: b.channel,
+	}
+}
@@ -0,0 +1,47 @@
+package gatewayapi
+
+import (
+	"github.com/blang/semver/v4"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway API Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the cluster.
+type addonState struct {
+	Channel Channel `json:"channel,omitempty"`
+}
+
+// State provides the state of the addon, its version is the Gateway API release.
+func (a *Addon) State() (clusters.AddonState, error) {
+	var version string
+	if a.version != nil {
+		version = a.version.String()
+	}
+	return clusters.NewAddonState(a.Name(), AddonName, version, addonState{
+		Channel: a.channel,
+	})
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+	a := &Addon{channel: opts.Channel}
+	if s.Version != "" {
+		version, err := semver.Parse(s.Version)
+		if err != nil {
+			return nil, err
+		}
+		a.version = &version
+	}
+	return a, nil
+}
@@ -78,6 +78,10 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	// generate a namespace name if the caller optioned for that
 	if a.generateNamespace {
 		a.namespace = uuid.New().String()
@@ -152,7 +156,7 @@ func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
 }
 
 func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) (waitForObjects []runtime.Object, ready bool, err error) {
-	return utils.IsNamespaceAvailable(ctx, cluster, a.namespace)
+	return utils.IsNamespaceAvailable(ctx, cluster, a.Name(), a.namespace)
 }
 
 func (a *Addon) DumpDiagnostics(context.Context, clusters.Cluster) (map[string][]byte, error) {
@@ -0,0 +1,44 @@
+package httpbin
+
+import (
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// HttpBin Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the
+// cluster. The namespace is the one the addon was deployed to, so a generated
+// namespace is not generated again.
+type addonState struct {
+	Namespace          string            `json:"namespace,omitempty"`
+	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty"`
+	Path               string            `json:"path,omitempty"`
+}
+
+// State provides the state of the addon.
+func (a *Addon) State() (clusters.AddonState, error) {
+	return clusters.NewAddonState(a.Name(), AddonName, "", addonState{
+		Namespace:          a.namespace,
+		IngressAnnotations: a.ingressAnnotations,
+		Path:               a.path,
+	})
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+	return &Addon{
+		name:               string(s.Name),
+		namespace:          opts.Namespace,
+		ingressAnnotations: opts.IngressAnnotations,
+		path:               opts.Path,
+	}, nil
+}
@@ -120,6 +120,10 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	// if an specific version was not provided we'll fetch and use the latest release tag
 	if a.istioVersion.String() == "0.0.0" {
 		if err := a.useLatestIstioVersion(ctx); err != nil {
@@ -219,7 +223,7 @@ func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
 }
 
 func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) (waitForObjects []runtime.Object, ready bool, err error) {
-	return utils.IsNamespaceAvailable(ctx, cluster, Namespace)
+	return utils.IsNamespaceAvailable(ctx, cluster, a.Name(), Namespace)
 }
 
 func (a *Addon) DumpDiagnostics(context.Context, clusters.Cluster) (map[string][]byte, error) {
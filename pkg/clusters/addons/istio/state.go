@@ -0,0 +1,71 @@
+package istio
+
+import (
+	"github.com/blang/semver/v4"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Istio Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the
+// cluster, along with the name of the deploy script and job so that they can
+// be cleaned up by Delete().
+type addonState struct {
+	DeployScriptName  string `json:"deployScriptName,omitempty"`
+	DeployJobName     string `json:"deployJobName,omitempty"`
+	PrometheusEnabled bool   `json:"prometheusEnabled,omitempty"`
+	GrafanaEnabled    bool   `json:"grafanaEnabled,omitempty"`
+	JaegerEnabled     bool   `json:"jaegerEnabled,omitempty"`
+	KialiEnabled      bool   `json:"kialiEnabled,omitempty"`
+}
+
+// State provides the state of the addon, its version is the istio release.
+func (a *Addon) State() (clusters.AddonState, error) {
+	opts := addonState{
+		PrometheusEnabled: a.prometheusEnabled,
+		GrafanaEnabled:    a.grafanaEnabled,
+		JaegerEnabled:     a.jaegerEnabled,
+		KialiEnabled:      a.kialiEnabled,
+	}
+	if a.istioDeployScript != nil {
+		opts.DeployScriptName = a.istioDeployScript.Name
+	}
+	if a.istioDeployJob != nil {
+		opts.DeployJobName = a.istioDeployJob.Name
+	}
+	return clusters.NewAddonState(a.Name(), AddonName, a.istioVersion.String(), opts)
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	version, err := semver.Parse(s.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Addon{
+		name:              string(s.Name),
+		istioVersion:      version,
+		istioDeployScript: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: opts.DeployScriptName}},
+		istioDeployJob:    &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: opts.DeployJobName}},
+
+		prometheusEnabled: opts.PrometheusEnabled,
+		grafanaEnabled:    opts.GrafanaEnabled,
+		jaegerEnabled:     opts.JaegerEnabled,
+		kialiEnabled:      opts.KialiEnabled,
+	}, nil
+}
@@ -55,6 +55,10 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	if a.version == "0.0.0" {
 		if err := a.useLatestKnativeVersion(ctx); err != nil {
 			return err
@@ -82,7 +86,7 @@ func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.
 	}
 
 	if len(waitingForObjects) > 0 {
-		return waitingForObjects, false, nil
+		return waitingForObjects, false, clusters.CheckForFailures(ctx, cluster, a.Name(), waitingForObjects)
 	}
 
 	return nil, true, nil
@@ -0,0 +1,22 @@
+package knative
+
+import (
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Knative Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// State provides the state of the addon, its version is the knative release.
+func (a *Addon) State() (clusters.AddonState, error) {
+	return clusters.NewAddonState(a.Name(), AddonName, a.version, nil)
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	return &Addon{version: s.Version}, nil
+}
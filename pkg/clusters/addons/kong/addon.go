@@ -1,26 +1,24 @@
 package kong
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/kong/go-database-reconciler/pkg/dump"
 	"github.com/kong/go-database-reconciler/pkg/file"
 	"github.com/kong/go-database-reconciler/pkg/state"
-	dbreconcilerutils "github.com/kong/go-database-reconciler/pkg/utils"
 	"github.com/kong/go-kong/kong"
 	pwgen "github.com/sethvargo/go-password/password"
 	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/strvals"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,8 +30,12 @@ import (
 	"github.com/kong/kubernetes-testing-framework/internal/retry"
 	"github.com/kong/kubernetes-testing-framework/internal/utils"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/certmanager"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/gatewayapi"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/helm"
 )
 
 // -----------------------------------------------------------------------------
@@ -78,8 +80,8 @@ type Addon struct {
 	// kubernetes and helm chart related configuration options
 	namespace       string
 	helmReleaseName string
-	deployArgs      []string
 	chartVersion    string
+	chartArchive    string
 
 	// ingress controller configuration options
 	ingressControllerDisabled bool
@@ -96,6 +98,7 @@ type Addon struct {
 	proxyServiceType                  corev1.ServiceType
 	proxyEnvVars                      map[string]string
 	proxyReadinessProbePath           string
+	customPlugins                     []customPlugin
 
 	// Node ports
 	httpNodePort  int
@@ -105,8 +108,33 @@ type Addon struct {
 	proxyEnterpriseEnabled            bool
 	proxyEnterpriseSuperAdminPassword string
 	proxyEnterpriseLicenseJSON        string
+
+	// enterprise RBAC entities which are created when the addon is deployed
+	workspaces []string
+	rbacRoles  []RBACRole
+	rbacUsers  []RBACUser
+
+	// adminClients caches the clients of AdminClient, it's shared by copies of
+	// the addon.
+	adminClients *adminClients
+
+	// hybridDataPlanes is the number of data plane releases deployed next to
+	// the control plane release in hybrid mode, or 0 for a single release.
+	hybridDataPlanes int
+
+	// clusterCertIssuer is the name of the cert-manager ClusterIssuer of the
+	// hybrid mode cluster certificate, or empty if it's self-signed.
+	clusterCertIssuer string
+
+	// gatewayClassName is the name of the GatewayClass of the ingress
+	// controller, or empty if the Gateway API isn't enabled.
+	gatewayClassName string
+
+	// valuesSources are the helm values merged over the addon's own values, in
+	// the order they were configured.
+	valuesSources []valuesSource
 	// additionalValues stores values that are set during installing by helm.
-	// for each key-value pair, an argument `--set <key>=<value>` is added.
+	// each key-value pair is parsed like helm's `--set <key>=<value>` flag.
 	additionalValues map[string]string
 }
 
@@ -130,13 +158,72 @@ func (a *Addon) Namespace() string {
 	return a.namespace
 }
 
+// -----------------------------------------------------------------------------
+// Kong Addon - Helm Configuration Methods
+// -----------------------------------------------------------------------------
+
+// Values provides the helm values which configure the chart release of the
+// addon for the cluster. Only the IP family of the cluster is taken into
+// account, so the values can be inspected without deploying anything.
+//
+// The values are merged in order of increasing precedence from:
+//
+//  1. the addon's own values, derived from the Builder's other options
+//  2. the values of WithValues, WithValuesFile and WithValuesYAML, in the
+//     order they were configured
+//  3. the values of WithAdditionalValue, like helm's --set flags
+//
+// Maps are merged recursively, while any other value (including lists)
+// replaces the value of lower precedence.
+//
+// In hybrid mode these are the values of the control plane's release, and the
+// values of WithValues, WithValuesFile, WithValuesYAML and WithAdditionalValue
+// apply to the data planes' releases (see DataPlaneValues) too.
+func (a *Addon) Values(cluster clusters.Cluster) (map[string]any, error) {
+	return a.values(cluster.IPFamily(), controlPlaneRelease)
+}
+
+// Template renders the chart with the addon's values (for an IPv4 cluster)
+// into the manifests which would be deployed, without using a cluster. The
+// chart must be a local archive configured with WithHelmChartArchive. In
+// hybrid mode the manifests of the data planes' releases follow the ones of
+// the control plane's release.
+func (a *Addon) Template(ctx context.Context) ([]byte, error) {
+	if a.chartArchive == "" {
+		return nil, fmt.Errorf("rendering the kong chart requires a local chart archive")
+	}
+	values, err := a.values(clusters.IPv4, controlPlaneRelease)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := helm.Template(ctx, a.helmReleaseName, a.namespace, a.chart(), values)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range a.hybridDataPlanes {
+		values, err := a.values(clusters.IPv4, i)
+		if err != nil {
+			return nil, err
+		}
+		dataPlaneManifests, err := helm.Template(ctx, a.dataPlaneReleaseName(i), a.namespace, a.chart(), values)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, dataPlaneManifests...)
+	}
+	return manifests, nil
+}
+
 // -----------------------------------------------------------------------------
 // Kong Addon - Proxy Endpoint Methods
 // -----------------------------------------------------------------------------
 
-// ProxyHTTPURL provides a routable *url.URL for accessing the Kong proxy.
+// ProxyHTTPURL provides a routable *url.URL for accessing the Kong proxy. In
+// hybrid mode the proxy URLs are the ones of the first data plane, see
+// DataPlaneProxyURLs for the others.
 func (a *Addon) ProxyHTTPURL(ctx context.Context, cluster clusters.Cluster) (*url.URL, error) {
-	urlStr, err := a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: DefaultProxyServiceName}, DefaultProxyHTTPPort)
+	urlStr, err := a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: a.proxyServiceName()}, DefaultProxyHTTPPort)
 	if err != nil {
 		return nil, err
 	}
@@ -145,16 +232,22 @@ func (a *Addon) ProxyHTTPURL(ctx context.Context, cluster clusters.Cluster) (*ur
 
 // ProxyHTTPSURL provides a routable *url.URL for accessing the Kong proxy.
 func (a *Addon) ProxyHTTPSURL(ctx context.Context, cluster clusters.Cluster) (*url.URL, error) {
-	urlStr, err := a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: DefaultProxyServiceName}, DefaultProxyTLSServicePort)
+	urlStr, err := a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: a.proxyServiceName()}, DefaultProxyTLSServicePort)
 	if err != nil {
 		return nil, err
 	}
 	return url.Parse(urlStr)
 }
 
-// ProxyAdminURL provides a routable *url.URL for accessing the Kong Admin API.
+// ProxyAdminURL provides a routable *url.URL for accessing the Kong Admin API,
+// which is the control plane's Admin API in hybrid mode.
 func (a *Addon) ProxyAdminURL(ctx context.Context, cluster clusters.Cluster) (*url.URL, error) {
-	urlStr, err := a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: DefaultAdminServiceName}, DefaultAdminServicePort)
+	// the Admin API may only be served over TLS if the values configure it so
+	port := DefaultAdminServicePort
+	if a.adminTLS(cluster) {
+		port = DefaultAdminTLSServicePort
+	}
+	urlStr, err := a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: chartFullname(a.helmReleaseName) + "-admin"}, port)
 	if err != nil {
 		return nil, err
 	}
@@ -163,18 +256,18 @@ func (a *Addon) ProxyAdminURL(ctx context.Context, cluster clusters.Cluster) (*u
 
 // ProxyUDPURL provides a routable address for accessing the default UDP service for the Kong Proxy.
 func (a *Addon) ProxyUDPURL(ctx context.Context, cluster clusters.Cluster) (string, error) {
-	return a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: DefaultUDPServiceName}, DefaultUDPServicePort)
+	return a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: a.udpProxyServiceName()}, DefaultUDPServicePort)
 }
 
 // ProxyTCPURL provides a routable address for accessing the default TCP service for the Kong Proxy.
 func (a *Addon) ProxyTCPURL(ctx context.Context, cluster clusters.Cluster) (string, error) {
 	// TCP port is exposed on the same service as the HTTP port.
-	return a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: DefaultProxyServiceName}, DefaultTCPServicePort)
+	return a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: a.proxyServiceName()}, DefaultTCPServicePort)
 }
 
 // ProxyTLSURL provides a routable address for accessing the Kong proxy over TLS.
 func (a *Addon) ProxyTLSURL(ctx context.Context, cluster clusters.Cluster) (string, error) {
-	return a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: DefaultProxyServiceName}, DefaultTLSServicePort)
+	return a.urlForService(ctx, cluster, types.NamespacedName{Namespace: a.namespace, Name: a.proxyServiceName()}, DefaultTLSServicePort)
 }
 
 // -----------------------------------------------------------------------------
@@ -186,37 +279,44 @@ func (a *Addon) Name() clusters.AddonName {
 }
 
 func (a *Addon) Dependencies(_ context.Context, cluster clusters.Cluster) []clusters.AddonName {
-	if _, ok := cluster.(*kind.Cluster); ok {
+	var dependencies []clusters.AddonName
+	switch cluster.Type() {
+	case kind.KindClusterType, k3d.K3dClusterType:
 		if a.proxyAdminServiceTypeLoadBalancer {
-			return []clusters.AddonName{
-				metallb.AddonName,
-			}
+			dependencies = append(dependencies, metallb.AddonName)
 		}
 	}
-	return nil
+
+	// the cluster certificate is issued by a cert-manager ClusterIssuer
+	if a.hybrid() && a.clusterCertIssuer != "" {
+		dependencies = append(dependencies, certmanager.AddonName)
+	}
+
+	// the Gateway API CRDs have to be installed before the controller starts
+	if a.gatewayClassName != "" {
+		dependencies = append(dependencies, gatewayapi.AddonName)
+	}
+
+	return dependencies
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	// wait for dependency addons to be ready first
 	if err := clusters.WaitForAddonDependencies(ctx, cluster, a); err != nil {
 		return fmt.Errorf("failure waiting for addon dependencies: %w", err)
 	}
 
-	// generate a temporary kubeconfig since we're going to be using the helm CLI
-	kubeconfig, err := clusters.TempKubeconfig(cluster)
-	if err != nil {
-		return err
-	}
-	defer os.Remove(kubeconfig.Name())
-
-	// ensure the repo exists
-	err = retry.Command("helm", "--kubeconfig", kubeconfig.Name(), "repo", "add", "--force-update", "kong", KongHelmRepoURL).Do(ctx)
+	// compile the helm installation values
+	values, err := a.Values(cluster)
 	if err != nil {
 		return err
 	}
 
-	// ensure all repos are up to date
-	err = retry.Command("helm", "--kubeconfig", kubeconfig.Name(), "repo", "update").Do(ctx)
+	helmClient, err := helm.NewClient(cluster.Config(), a.namespace, a.logger.Debugf)
 	if err != nil {
 		return err
 	}
@@ -227,214 +327,176 @@ func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
 		return err
 	}
 
-	// Pin the chart version if specified.
-	if a.chartVersion != "" {
-		a.deployArgs = append(a.deployArgs, "--version", a.chartVersion)
+	if err := a.deploySecrets(ctx, cluster); err != nil {
+		return err
+	}
+	if err := a.deployCustomPlugins(ctx, cluster); err != nil {
+		return err
 	}
 
-	if a.proxyPullSecret != (pullSecret{}) {
-		// create the pull Secret
-		opts := create.CreateSecretDockerRegistryOptions{
-			Name:       ProxyPullSecretName,
-			Namespace:  a.namespace,
-			Client:     cluster.Client().CoreV1(),
-			PrintFlags: genericclioptions.NewPrintFlags("created").WithTypeSetter(scheme.Scheme),
-			Username:   a.proxyPullSecret.Username,
-			Email:      a.proxyPullSecret.Email,
-			Password:   a.proxyPullSecret.Password,
-			Server:     a.proxyPullSecret.Server,
-		}
-		if opts.Server == "" {
-			opts.Server = "https://index.docker.io/v1/"
-		}
-		opts.PrintObj = func(_ runtime.Object) error {
-			return nil
-		}
+	a.logger.Debugf("helm install values: %+v", values)
+	if err := a.installOrUpgrade(ctx, helmClient, a.helmReleaseName, values, helm.Options{
+		SkipCRDs: a.ingressControllerDisabled,
+		Wait:     a.hybrid(),
+	}); err != nil {
+		return err
+	}
 
-		if err := opts.Run(); err != nil {
+	// in hybrid mode the data planes are deployed once the control plane
+	// they connect to is ready.
+	for i := range a.hybridDataPlanes {
+		values, err := a.values(cluster.IPFamily(), i)
+		if err != nil {
+			return err
+		}
+		a.logger.Debugf("helm install values of data plane %d: %+v", i, values)
+		if err := a.installOrUpgrade(ctx, helmClient, a.dataPlaneReleaseName(i), values, helm.Options{
+			SkipCRDs: true,
+		}); err != nil {
 			return err
 		}
-
-		// use the pull Secret
-		a.deployArgs = append(a.deployArgs,
-			"--set", "image.pullSecrets={proxy-pull}",
-		)
-	}
-
-	// if the dbmode is postgres, set several related values
-	args := []string{"--kubeconfig", kubeconfig.Name(), "upgrade", "--install", a.helmReleaseName, "kong/kong"}
-	if a.proxyDBMode == PostgreSQL {
-		a.deployArgs = append(a.deployArgs,
-			"--set", "env.database=postgres",
-			"--set", "postgresql.enabled=true",
-			"--set", "postgresql.auth.username=kong",
-			"--set", "postgresql.auth.database=kong",
-			"--set", "postgresql.service.port=5432",
-			// Set PostgreSQL image, registry and tag to some sane defaults,
-			// because now, after https://github.com/Kong/charts/issues/1400
-			// the chart does not specify any default image for PostgreSQL.
-			"--set", "postgresql.image.registry=registry-1.docker.io",
-			"--set", "postgresql.image.repository=bitnamilegacy/postgresql",
-			"--set", "postgresql.image.tag=13.11.0-debian-11-r83",
-		)
 	}
 
-	if cluster.IPFamily() == clusters.IPv6 {
-		a.deployArgs = append(a.deployArgs,
-			"--set", "proxy.address=[::]",
-			"--set", "admin.address=[::1]",
-			"--set", "status.address=[::]",
-			"--set", "cluster.address=[::]",
-			"--set", "ingressController.admissionWebhook.address=[::]",
-		)
+	if a.gatewayClassName != "" {
+		if err := a.deployGatewayClass(ctx, cluster); err != nil {
+			return err
+		}
 	}
 
-	// if the ingress controller is disabled flag it in the chart and don't install any CRDs
-	if a.ingressControllerDisabled {
-		a.deployArgs = append(a.deployArgs,
-			"--set", "ingressController.enabled=false",
-			"--set", "ingressController.installCRDs=false",
-			"--skip-crds",
-		)
+	// the RBAC entities are created through the Admin API once it's up
+	if a.provisionsRBAC() {
+		if err := a.provisionRBAC(ctx, cluster); err != nil {
+			return err
+		}
 	}
 
-	// set the ingress controller container image values if provided by the caller
-	if a.ingressControllerImage != "" {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("ingressController.image.repository=%s", a.ingressControllerImage))
-	}
-	if a.ingressControllerImageTag != "" {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("ingressController.image.tag=%s", a.ingressControllerImageTag))
-	}
+	return nil
+}
 
-	// set the container image values if provided by the caller
-	if a.proxyImage != "" {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("image.repository=%s", a.proxyImage))
+// Upgrade upgrades the deployed chart release to the configuration of the
+// target addon (e.g. a new chart version or new proxy and controller images)
+// and waits until the upgraded release is ready. The namespace, the release
+// name, the DB mode and the enterprise configuration can't be changed.
+func (a *Addon) Upgrade(ctx context.Context, cluster clusters.Cluster, target clusters.Addon) error {
+	t, ok := target.(*Addon)
+	if !ok {
+		return fmt.Errorf("can't upgrade the kong addon to a %T", target)
 	}
-	if a.proxyImageTag != "" {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("image.tag=%s", a.proxyImageTag))
+	if err := a.validateUpgrade(t); err != nil {
+		return err
 	}
 
-	// set the service type of the proxy admin's Kubernetes service
-	if a.proxyAdminServiceTypeLoadBalancer {
-		a.deployArgs = append(a.deployArgs, "--set", "admin.type=LoadBalancer")
-	} else {
-		a.deployArgs = append(a.deployArgs, "--set", "admin.type=ClusterIP")
+	values, err := t.Values(cluster)
+	if err != nil {
+		return err
 	}
 
-	// set the service type of the proxy's Kubernetes service
-	if a.proxyServiceType == corev1.ServiceTypeExternalName {
-		return fmt.Errorf("Service type ExternalName is not currently supported") //nolint:staticcheck
+	helmClient, err := helm.NewClient(cluster.Config(), a.namespace, a.logger.Debugf)
+	if err != nil {
+		return err
 	}
-	a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("proxy.type=%s", a.proxyServiceType))
-	a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("udpProxy.type=%s", a.proxyServiceType))
 
-	// set the proxy log level
-	if len(a.proxyLogLevel) > 0 {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("env.log_level=%s", a.proxyLogLevel))
+	// the ConfigMaps of the target's custom plugins are mounted by the new pods
+	if err := t.deployCustomPlugins(ctx, cluster); err != nil {
+		return err
 	}
 
-	// Set the proxy readiness probe path.
-	if len(a.proxyReadinessProbePath) > 0 {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("readinessProbe.httpGet.path=%s", a.proxyReadinessProbePath))
+	// helm waits for the new pods to be ready, so the old ones serve traffic meanwhile
+	a.logger.Debugf("helm upgrade values: %+v", values)
+	if err := helmClient.Upgrade(ctx, t.helmReleaseName, t.chart(), values, helm.Options{
+		SkipCRDs: t.ingressControllerDisabled,
+		Wait:     true,
+	}); err != nil {
+		return err
 	}
 
-	// Deploy licenses and other configurations for enterprise mode.
-	if a.proxyEnterpriseEnabled {
-		// Set the enterprise defaults helm installation values.
-		a.deployArgs = append(a.deployArgs, enterpriseDefaults()...)
-		// Deploy the license as a Kubernetes secret to enable enterprise features for the proxy.
-		if err := deployKongEnterpriseLicenseSecret(ctx, cluster, a.namespace, DefaultEnterpriseLicenseSecretName, a.proxyEnterpriseLicenseJSON); err != nil {
+	// in hybrid mode the control plane is upgraded before the data planes, as
+	// newer control planes support older data planes but not vice versa.
+	for i := range t.hybridDataPlanes {
+		values, err := t.values(cluster.IPFamily(), i)
+		if err != nil {
 			return err
 		}
-		// For DB-less mode, admin password can't be configured because there is nowhere for it to be stored.
-		if a.proxyDBMode != DBLESS {
-			// Deploy the superadmin password as a Kubernetes secret adjacent to the proxy pod and configure
-			// the chart to use that secret to configure controller auth to the admin API.
-			a.proxyEnterpriseSuperAdminPassword, err = deployEnterpriseSuperAdminPasswordSecret(ctx, cluster, a.namespace, a.proxyEnterpriseSuperAdminPassword)
-			if err != nil {
-				return err
-			}
-			a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("env.password.valueFrom.secretKeyRef.name=%s", DefaultEnterpriseAdminPasswordSecretName))
-			a.deployArgs = append(a.deployArgs, "--set", "env.password.valueFrom.secretKeyRef.key=password")
-			a.deployArgs = append(a.deployArgs, "--set", "enterprise.rbac.enabled=true", "--set", "env.enforce_rbac=on")
-			if !a.ingressControllerDisabled {
-				a.deployArgs = append(
-					a.deployArgs,
-					"--set", fmt.Sprintf("ingressController.env.kong_admin_token.valueFrom.secretKeyRef.name=%s", DefaultEnterpriseAdminPasswordSecretName),
-				)
-				a.deployArgs = append(a.deployArgs, "--set", "ingressController.env.kong_admin_token.valueFrom.secretKeyRef.key=password")
-			}
+		a.logger.Debugf("helm upgrade values of data plane %d: %+v", i, values)
+		if err := helmClient.Upgrade(ctx, t.dataPlaneReleaseName(i), t.chart(), values, helm.Options{
+			SkipCRDs: true,
+			Wait:     true,
+		}); err != nil {
+			return err
 		}
+	}
 
-		// Set the session configuration secret name for the admin GUI.
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("enterprise.rbac.session_conf_secret=%s", DefaultAdminGUISessionConfSecretName))
-
-		// Deploy the admin session configuration needed for enterprise enabled mode.
-		if err := deployKongEnterpriseAdminGUISessionConf(ctx, cluster, a.namespace); err != nil {
+	// the GatewayClass follows the target's name, if any
+	if t.gatewayClassName != "" {
+		if err := t.deployGatewayClass(ctx, cluster); err != nil {
 			return err
 		}
-
 	}
-
-	for name, value := range a.proxyEnvVars {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("env.%s=%s", name, value))
+	if a.gatewayClassName != "" && a.gatewayClassName != t.gatewayClassName {
+		if err := a.deleteGatewayClass(ctx, cluster); err != nil {
+			return err
+		}
 	}
 
-	for name, value := range a.additionalValues {
-		a.deployArgs = append(a.deployArgs, "--set", fmt.Sprintf("%s=%s", name, value))
+	// the deployed addon now reflects the target, except for the generated
+	// superadmin password and RBAC user tokens which only the deployed addon
+	// knows.
+	password, rbacUsers := a.proxyEnterpriseSuperAdminPassword, a.rbacUsers
+	*a = *t
+	if password != "" {
+		a.proxyEnterpriseSuperAdminPassword = password
 	}
+	a.rbacUsers = rbacUsers
 
-	// compile the helm installation values
-	args = append(args, "--namespace", a.namespace)
-	args = append(args, a.deployArgs...)
-	args = append(args, defaults()...)
+	return nil
+}
 
-	if a.httpNodePort > 0 {
-		args = append(args, "--set", fmt.Sprintf("proxy.http.nodePort=%d", a.httpNodePort))
+func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
+	helmClient, err := helm.NewClient(cluster.Config(), a.namespace, a.logger.Debugf)
+	if err != nil {
+		return err
 	}
-	if a.adminNodePort > 0 {
-		args = append(args, "--set", fmt.Sprintf("admin.http.nodePort=%d", a.adminNodePort))
+
+	if a.gatewayClassName != "" {
+		if err := a.deleteGatewayClass(ctx, cluster); err != nil {
+			return err
+		}
 	}
 
-	args = append(args, exposePortsDefault()...)
-	a.logger.Debugf("helm install arguments: %+v", args)
+	// delete the chart releases from the cluster, the data planes first
+	for i := range a.hybridDataPlanes {
+		if err := helmClient.Uninstall(a.dataPlaneReleaseName(i)); err != nil {
+			return err
+		}
+	}
+	if err := helmClient.Uninstall(a.helmReleaseName); err != nil {
+		return err
+	}
 
-	// Sometimes running helm install fails. Just in case this happens, retry.
-	return retry.
-		Command("helm", args...).
-		DoWithErrorHandling(ctx, func(err error, _, stderr *bytes.Buffer) error {
-			// ignore if addon is already deployed
-			if strings.Contains(stderr.String(), "cannot re-use") {
-				return nil
-			}
-			return fmt.Errorf("%s: %w", stderr, err)
-		})
-}
+	if a.hybrid() {
+		if err := a.deleteClusterCertSecret(ctx, cluster); err != nil {
+			return err
+		}
+	}
 
-func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
-	// generate a temporary kubeconfig since we're going to be using the helm CLI
-	kubeconfig, err := clusters.TempKubeconfig(cluster)
-	if err != nil {
-		return err
+	if len(a.customPlugins) > 0 {
+		if err := a.deleteCustomPlugins(ctx, cluster); err != nil {
+			return err
+		}
 	}
-	defer os.Remove(kubeconfig.Name())
 
-	// delete the chart release from the cluster
-	stderr := new(bytes.Buffer)
-	cmd := exec.CommandContext(ctx, "helm", "--kubeconfig", kubeconfig.Name(), "uninstall", a.helmReleaseName, "--namespace", a.namespace) //nolint:gosec
-	cmd.Stdout = io.Discard
-	cmd.Stderr = stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %w", stderr.String(), err)
+	if len(a.rbacUsers) > 0 {
+		if err := a.deleteRBACUserTokensSecret(ctx, cluster); err != nil {
+			return err
+		}
 	}
 
-	if a.proxyEnterpriseLicenseJSON != "" {
-		stderr := new(bytes.Buffer)
-		cmd = exec.Command("kubectl", "delete", "secret", DefaultEnterpriseLicenseSecretName, "--namespace", a.namespace, "--kubeconfig", kubeconfig.Name()) //nolint:gosec
-		cmd.Stdout = io.Discard
-		cmd.Stderr = stderr
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("delete secret err msg %s: %w", stderr.String(), err)
+	// the license itself isn't recorded in the addon state, so rehydrated addons
+	// rely on the enterprise flag to clean it up.
+	if a.proxyEnterpriseEnabled {
+		err := cluster.Client().CoreV1().Secrets(a.namespace).Delete(ctx, DefaultEnterpriseLicenseSecretName, metav1.DeleteOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to delete the enterprise license secret: %w", err)
 		}
 	}
 
@@ -442,32 +504,35 @@ func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
 }
 
 func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) (waitForObjects []runtime.Object, ready bool, err error) {
-	return utils.IsNamespaceAvailable(ctx, cluster, a.namespace)
+	return utils.IsNamespaceAvailable(ctx, cluster, a.Name(), a.namespace)
 }
 
 func (a *Addon) DumpDiagnostics(ctx context.Context, cluster clusters.Cluster) (map[string][]byte, error) {
 	diagnostics := make(map[string][]byte)
-	admin, err := a.ProxyAdminURL(ctx, cluster)
+	client, err := a.AdminClient(ctx, cluster, "")
 	if err != nil {
 		return diagnostics, fmt.Errorf("could not build diagnostic Kong client: %w", err)
 	}
-	resp, err := http.Get(admin.String() + "/")
+	root, err := client.RootJSON(ctx)
 	if err != nil {
 		return diagnostics, fmt.Errorf("could not retrieve Kong root: %w", err)
 	}
-	defer resp.Body.Close()
-	b := new(bytes.Buffer)
-	_, err = b.ReadFrom(resp.Body)
-	if err != nil {
-		return diagnostics, err
+	diagnostics["root_endpoint.json"] = root
+
+	// the control plane reports the data planes connected to it
+	if a.hybrid() {
+		dataPlanes, err := adminGet(ctx, client, "/clustering/data-planes")
+		if err != nil {
+			return diagnostics, fmt.Errorf("could not retrieve Kong data planes: %w", err)
+		}
+		diagnostics["clustering_data_planes.json"] = dataPlanes
 	}
-	diagnostics["root_endpoint.json"] = b.Bytes()
 
 	// Extract the version from the root endpoint.
 	var kongVersion struct {
 		Version string `json:"version"`
 	}
-	err = json.Unmarshal(b.Bytes(), &kongVersion)
+	err = json.Unmarshal(root, &kongVersion)
 	if err != nil {
 		return diagnostics, fmt.Errorf("could not unmarshal Kong version: %w", err)
 	}
@@ -480,26 +545,6 @@ func (a *Addon) DumpDiagnostics(ctx context.Context, cluster clusters.Cluster) (
 		}
 		defer os.Remove(out.Name())
 		dumpConfig := dump.Config{}
-		addr, err := a.ProxyAdminURL(ctx, cluster)
-		if err != nil {
-			return diagnostics, fmt.Errorf("could not build Kong client: %w", err)
-		}
-		opts := dbreconcilerutils.KongClientConfig{
-			Address: addr.String(),
-			HTTPClient: &http.Client{
-				Timeout: time.Second * 90, //nolint:mnd
-			},
-			TLSConfig: dbreconcilerutils.TLSConfig{
-				SkipVerify: true,
-			},
-		}
-		if a.proxyEnterpriseSuperAdminPassword != "" {
-			opts.Headers = append(opts.Headers, "kong-admin-token:"+a.proxyEnterpriseSuperAdminPassword)
-		}
-		client, err := dbreconcilerutils.GetKongClient(opts)
-		if err != nil {
-			return diagnostics, fmt.Errorf("could not build Kong client: %w", err)
-		}
 		workspaces, err := client.Workspaces.ListAll(ctx)
 		var kongAPIError *kong.APIError
 		if errors.As(err, &kongAPIError) && kongAPIError.Code() == http.StatusNotFound {
@@ -510,17 +555,9 @@ func (a *Addon) DumpDiagnostics(ctx context.Context, cluster clusters.Cluster) (
 		}
 
 		for _, workspace := range workspaces {
-			wsOpts := opts
-			wsOpts.Workspace = *workspace.Name
-			var wsClient *kong.Client
-			if *workspace.Name == "default" {
-				// arguably a workspaced client for default should work on OSS, but it doesn't!
-				wsClient = client
-			} else {
-				wsClient, err = dbreconcilerutils.GetKongClient(wsOpts)
-				if err != nil {
-					return diagnostics, fmt.Errorf("could not build Kong client: %w", err)
-				}
+			wsClient, err := a.AdminClient(ctx, cluster, *workspace.Name)
+			if err != nil {
+				return diagnostics, fmt.Errorf("could not build Kong client: %w", err)
 			}
 			// deck will forcibly append the extension if you omit it
 			out, err := os.CreateTemp(os.TempDir(), "ktf-kong-config-*.yaml")
@@ -551,15 +588,14 @@ func (a *Addon) DumpDiagnostics(ctx context.Context, cluster clusters.Cluster) (
 			diagnostics[*workspace.Name+"_pg_config.yaml"] = config
 		}
 	case DBLESS:
-		resp, err := http.Get(admin.String() + "/config")
+		config, err := adminGet(ctx, client, "/config")
 		if err != nil {
 			return diagnostics, fmt.Errorf("could not retrieve Kong /config: %w", err)
 		}
-		defer resp.Body.Close()
 		var kongConfig struct {
 			Config string `json:"config,omitempty" yaml:"config,omitempty"`
 		}
-		err = json.NewDecoder(resp.Body).Decode(&kongConfig)
+		err = json.Unmarshal(config, &kongConfig)
 		if err != nil {
 			return diagnostics, fmt.Errorf("could not parse config: %w", err)
 		}
@@ -588,7 +624,7 @@ func (a *Addon) urlForService(ctx context.Context, cluster clusters.Cluster, nsn
 	switch port {
 	case DefaultAdminServicePort, DefaultProxyHTTPPort:
 		urlSchemePrefix = "http://"
-	case DefaultProxyTLSServicePort:
+	case DefaultProxyTLSServicePort, DefaultAdminTLSServicePort:
 		urlSchemePrefix = "https://"
 	default:
 		// To make URL parsable and without scheme, it is expected to start with "//".
@@ -629,6 +665,318 @@ const (
 	secretAllowRepeat = false
 )
 
+// -----------------------------------------------------------------------------
+// Kong Addon - Private Deployment Methods
+// -----------------------------------------------------------------------------
+
+// deploySecrets deploys the Secrets which the chart release refers to, such as
+// the image pull secret and the enterprise license.
+func (a *Addon) deploySecrets(ctx context.Context, cluster clusters.Cluster) error {
+	if a.proxyPullSecret != (pullSecret{}) {
+		// create the pull Secret
+		opts := create.CreateSecretDockerRegistryOptions{
+			Name:       ProxyPullSecretName,
+			Namespace:  a.namespace,
+			Client:     cluster.Client().CoreV1(),
+			PrintFlags: genericclioptions.NewPrintFlags("created").WithTypeSetter(scheme.Scheme),
+			Username:   a.proxyPullSecret.Username,
+			Email:      a.proxyPullSecret.Email,
+			Password:   a.proxyPullSecret.Password,
+			Server:     a.proxyPullSecret.Server,
+		}
+		if opts.Server == "" {
+			opts.Server = "https://index.docker.io/v1/"
+		}
+		opts.PrintObj = func(_ runtime.Object) error {
+			return nil
+		}
+
+		if err := opts.Run(); err != nil {
+			return err
+		}
+	}
+
+	// Deploy the certificate the control plane and data planes authenticate each other with.
+	if a.hybrid() {
+		if err := a.deployClusterCertSecret(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
+	// Deploy licenses and other configurations for enterprise mode.
+	if a.proxyEnterpriseEnabled {
+		// Deploy the license as a Kubernetes secret to enable enterprise features for the proxy.
+		if err := deployKongEnterpriseLicenseSecret(ctx, cluster, a.namespace, DefaultEnterpriseLicenseSecretName, a.proxyEnterpriseLicenseJSON); err != nil {
+			return err
+		}
+		// For DB-less mode, admin password can't be configured because there is nowhere for it to be stored.
+		if a.proxyDBMode != DBLESS {
+			// Deploy the superadmin password as a Kubernetes secret adjacent to the proxy pod.
+			var err error
+			a.proxyEnterpriseSuperAdminPassword, err = deployEnterpriseSuperAdminPasswordSecret(ctx, cluster, a.namespace, a.proxyEnterpriseSuperAdminPassword)
+			if err != nil {
+				return err
+			}
+			if len(a.rbacUsers) > 0 {
+				if err := a.deployRBACUserTokensSecret(ctx, cluster); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Deploy the admin session configuration needed for enterprise enabled mode.
+		if err := deployKongEnterpriseAdminGUISessionConf(ctx, cluster, a.namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// values provides the helm values for a cluster of the IP family, for the
+// release of the data plane with the given index or for controlPlaneRelease.
+// It doesn't modify the addon, so deploying or upgrading it repeatedly uses the
+// same values.
+func (a *Addon) values(ipFamily clusters.IPFamily, dataPlane int) (map[string]any, error) {
+	// the data planes are DB-less, and get their configuration from the
+	// control plane which stores it in its database.
+	isDataPlane := dataPlane != controlPlaneRelease
+	if a.hybrid() && a.proxyDBMode != PostgreSQL {
+		return nil, fmt.Errorf("the control plane of the kong addon requires the %s DB mode in hybrid mode", PostgreSQL)
+	}
+	if a.clusterCertIssuer != "" && !a.hybrid() {
+		return nil, fmt.Errorf("the kong addon %s only has a cluster certificate in hybrid mode", a.name)
+	}
+
+	var sets []string
+
+	// use the pull Secret
+	if a.proxyPullSecret != (pullSecret{}) {
+		sets = append(sets,
+			"image.pullSecrets={proxy-pull}",
+		)
+	}
+
+	// if the dbmode is postgres, set several related values
+	if a.proxyDBMode == PostgreSQL && !isDataPlane {
+		sets = append(sets,
+			"env.database=postgres",
+			"postgresql.enabled=true",
+			"postgresql.auth.username=kong",
+			"postgresql.auth.database=kong",
+			"postgresql.service.port=5432",
+			// Set PostgreSQL image, registry and tag to some sane defaults,
+			// because now, after https://github.com/Kong/charts/issues/1400
+			// the chart does not specify any default image for PostgreSQL.
+			"postgresql.image.registry=registry-1.docker.io",
+			"postgresql.image.repository=bitnamilegacy/postgresql",
+			"postgresql.image.tag=13.11.0-debian-11-r83",
+		)
+	}
+
+	switch ipFamily {
+	case clusters.IPv6:
+		sets = append(sets,
+			"proxy.address=[::]",
+			"admin.address=[::1]",
+			"status.address=[::]",
+			"cluster.address=[::]",
+			"ingressController.admissionWebhook.address=[::]",
+		)
+	case clusters.Dual:
+		sets = append(sets, dualStackDefaults()...)
+	case clusters.IPv4:
+	}
+
+	// if the ingress controller is disabled flag it in the chart and don't install any CRDs
+	if a.ingressControllerDisabled || isDataPlane {
+		sets = append(sets,
+			"ingressController.enabled=false",
+			"ingressController.installCRDs=false",
+		)
+	}
+
+	// RBAC entities can only be created if RBAC is enforced
+	if a.provisionsRBAC() && !a.rbacEnforced() {
+		return nil, fmt.Errorf("the kong addon %s requires enterprise mode with a database for RBAC entities", a.name)
+	}
+
+	// enable the Gateway API support of the ingress controller
+	if a.gatewayClassName != "" && !isDataPlane {
+		if a.ingressControllerDisabled {
+			return nil, fmt.Errorf("the kong addon %s requires the ingress controller for the Gateway API", a.name)
+		}
+		sets = append(sets, a.gatewayAPIValues()...)
+	}
+
+	// set the ingress controller container image values if provided by the caller
+	if a.ingressControllerImage != "" {
+		sets = append(sets, fmt.Sprintf("ingressController.image.repository=%s", a.ingressControllerImage))
+	}
+	if a.ingressControllerImageTag != "" {
+		sets = append(sets, fmt.Sprintf("ingressController.image.tag=%s", a.ingressControllerImageTag))
+	}
+
+	// set the container image values if provided by the caller
+	if a.proxyImage != "" {
+		sets = append(sets, fmt.Sprintf("image.repository=%s", a.proxyImage))
+	}
+	if a.proxyImageTag != "" {
+		sets = append(sets, fmt.Sprintf("image.tag=%s", a.proxyImageTag))
+	}
+
+	// set the service type of the proxy admin's Kubernetes service
+	if a.proxyAdminServiceTypeLoadBalancer {
+		sets = append(sets, "admin.type=LoadBalancer")
+	} else {
+		sets = append(sets, "admin.type=ClusterIP")
+	}
+
+	// set the service type of the proxy's Kubernetes service
+	if a.proxyServiceType == corev1.ServiceTypeExternalName {
+		return nil, fmt.Errorf("Service type ExternalName is not currently supported") //nolint:staticcheck
+	}
+	sets = append(sets, fmt.Sprintf("proxy.type=%s", a.proxyServiceType))
+	sets = append(sets, fmt.Sprintf("udpProxy.type=%s", a.proxyServiceType))
+
+	// set the proxy log level
+	if len(a.proxyLogLevel) > 0 {
+		sets = append(sets, fmt.Sprintf("env.log_level=%s", a.proxyLogLevel))
+	}
+
+	// Set the proxy readiness probe path.
+	if len(a.proxyReadinessProbePath) > 0 {
+		sets = append(sets, fmt.Sprintf("readinessProbe.httpGet.path=%s", a.proxyReadinessProbePath))
+	}
+
+	if a.proxyEnterpriseEnabled {
+		// Set the enterprise defaults helm installation values.
+		sets = append(sets, enterpriseDefaults()...)
+		if a.proxyDBMode != DBLESS && !isDataPlane {
+			// configure the chart to use the superadmin password secret to configure controller auth to the admin API.
+			sets = append(sets, fmt.Sprintf("env.password.valueFrom.secretKeyRef.name=%s", DefaultEnterpriseAdminPasswordSecretName))
+			sets = append(sets, "env.password.valueFrom.secretKeyRef.key=password")
+			sets = append(sets, "enterprise.rbac.enabled=true", "env.enforce_rbac=on")
+			if !a.ingressControllerDisabled {
+				sets = append(
+					sets,
+					fmt.Sprintf("ingressController.env.kong_admin_token.valueFrom.secretKeyRef.name=%s", DefaultEnterpriseAdminPasswordSecretName),
+				)
+				sets = append(sets, "ingressController.env.kong_admin_token.valueFrom.secretKeyRef.key=password")
+			}
+		}
+
+		// Set the session configuration secret name for the admin GUI.
+		sets = append(sets, fmt.Sprintf("enterprise.rbac.session_conf_secret=%s", DefaultAdminGUISessionConfSecretName))
+	}
+
+	// kong.conf lists (e.g. plugins) are comma separated, so the commas are
+	// escaped as the values are parsed like --set flags
+	for _, name := range slices.Sorted(maps.Keys(a.proxyEnvVars)) {
+		sets = append(sets, fmt.Sprintf("env.%s=%s", name, strings.ReplaceAll(a.proxyEnvVars[name], ",", `\,`)))
+	}
+
+	// mount the custom plugins, which all the releases load in hybrid mode
+	if len(a.customPlugins) > 0 {
+		pluginSets, err := a.customPluginValues()
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, pluginSets...)
+	}
+
+	sets = append(sets, defaults()...)
+	if a.hybrid() {
+		sets = append(sets, a.hybridValues(dataPlane)...)
+	}
+
+	// each data plane has its own proxy Service, so they use consecutive node ports
+	if a.httpNodePort > 0 {
+		sets = append(sets, fmt.Sprintf("proxy.http.nodePort=%d", a.httpNodePort+max(dataPlane, 0)))
+	}
+	if a.adminNodePort > 0 && !isDataPlane {
+		sets = append(sets, fmt.Sprintf("admin.http.nodePort=%d", a.adminNodePort))
+	}
+	sets = append(sets, exposePortsDefault()...)
+
+	// the values are parsed like helm's --set flags
+	values := make(map[string]any)
+	for _, set := range sets {
+		if err := strvals.ParseInto(set, values); err != nil {
+			return nil, fmt.Errorf("failed to parse helm value %q: %w", set, err)
+		}
+	}
+
+	// the values provided by the caller take precedence, see Values()
+	userValues, err := a.userValues()
+	if err != nil {
+		return nil, err
+	}
+	values = helm.MergeValues(values, userValues)
+
+	for _, name := range slices.Sorted(maps.Keys(a.additionalValues)) {
+		set := fmt.Sprintf("%s=%s", name, a.additionalValues[name])
+		if err := strvals.ParseInto(set, values); err != nil {
+			return nil, fmt.Errorf("failed to parse helm value %q: %w", set, err)
+		}
+	}
+	return values, nil
+}
+
+// chart provides the chart which the addon deploys.
+func (a *Addon) chart() helm.Chart {
+	return helm.Chart{RepoURL: KongHelmRepoURL, Name: "kong", Version: a.chartVersion, Archive: a.chartArchive}
+}
+
+// installOrUpgrade installs or upgrades a release of the chart. Sometimes
+// installing the chart fails. Just in case this happens, retry: the failed
+// release of an attempt is kept for diagnosis and upgraded by the next one.
+func (a *Addon) installOrUpgrade(ctx context.Context, helmClient *helm.Client, name string, values map[string]any, opts helm.Options) error {
+	return retry.Do(ctx, func() error {
+		return helmClient.InstallOrUpgrade(ctx, name, a.chart(), values, opts)
+	})
+}
+
+// validateUpgrade verifies that the deployed addon can be upgraded in place to
+// the target addon.
+func (a *Addon) validateUpgrade(target *Addon) error {
+	var immutable []string
+	if a.name != target.name {
+		immutable = append(immutable, "name")
+	}
+	if a.namespace != target.namespace {
+		immutable = append(immutable, "namespace")
+	}
+	if a.helmReleaseName != target.helmReleaseName {
+		immutable = append(immutable, "helm release name")
+	}
+	if a.proxyDBMode != target.proxyDBMode {
+		immutable = append(immutable, "DB mode")
+	}
+	if a.proxyEnterpriseEnabled != target.proxyEnterpriseEnabled {
+		immutable = append(immutable, "enterprise mode")
+	}
+	if a.hybridDataPlanes != target.hybridDataPlanes {
+		immutable = append(immutable, "number of hybrid mode data planes")
+	}
+	if a.clusterCertIssuer != target.clusterCertIssuer {
+		immutable = append(immutable, "cluster certificate issuer")
+	}
+	if target.proxyEnterpriseSuperAdminPassword != "" && a.proxyEnterpriseSuperAdminPassword != target.proxyEnterpriseSuperAdminPassword {
+		immutable = append(immutable, "enterprise superadmin password")
+	}
+	if target.proxyPullSecret != (pullSecret{}) && a.proxyPullSecret != target.proxyPullSecret {
+		immutable = append(immutable, "image pull secret")
+	}
+	if !a.sameRBAC(target) {
+		immutable = append(immutable, "RBAC entities")
+	}
+	if len(immutable) > 0 {
+		return fmt.Errorf("the %s of the kong addon can't be changed by an upgrade", strings.Join(immutable, ", "))
+	}
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // Kong Addon - Private Functions
 // -----------------------------------------------------------------------------
@@ -641,20 +989,46 @@ func defaults() []string {
 	return []string{
 		// we configure a cluster network exposed admin API over HTTP with no auth for testing convenience,
 		// but again keep in mind this is meant ONLY for transient testing scenarios and isn't secure.
-		"--set", "admin.enabled=true",
-		"--set", "admin.http.enabled=true",
-		"--set", "admin.tls.enabled=false",
-		"--set", "tls.enabled=false",
-		"--set", "udpProxy.enabled=true",
+		"admin.enabled=true",
+		"admin.http.enabled=true",
+		"admin.tls.enabled=false",
+		"tls.enabled=false",
+		"udpProxy.enabled=true",
 	}
 }
 
 // enterpriseDefaults provides the default values needed to enable enterprise mode for the Kong proxy server.
 func enterpriseDefaults() []string {
 	return []string{
-		"--set", "enterprise.enabled=true",
-		"--set", "admin.annotations.konghq.com/protocol=http",
-		"--set", fmt.Sprintf("enterprise.license_secret=%s", DefaultEnterpriseLicenseSecretName),
+		"enterprise.enabled=true",
+		`admin.annotations.konghq\.com/protocol=http`,
+		fmt.Sprintf("enterprise.license_secret=%s", DefaultEnterpriseLicenseSecretName),
+	}
+}
+
+// dualStackDefaults provides the values needed for the Kong proxy to accept both
+// IPv4 and IPv6 connections and for its Services to get an address of each family.
+// nginx only accepts IPv6 connections on "[::]" listens unless ipv6only is off.
+func dualStackDefaults() []string {
+	return []string{
+		"proxy.address=[::]",
+		"proxy.http.parameters[0]=ipv6only=off",
+		"proxy.tls.parameters[0]=http2",
+		"proxy.tls.parameters[1]=ipv6only=off",
+		"proxy.stream[0].parameters[0]=ipv6only=off",
+		"proxy.stream[1].parameters[2]=ipv6only=off",
+		"proxy.ipFamilyPolicy=PreferDualStack",
+		"udpProxy.address=[::]",
+		"udpProxy.stream[0].parameters[2]=ipv6only=off",
+		"udpProxy.ipFamilyPolicy=PreferDualStack",
+		"admin.address=[::]",
+		"admin.http.parameters[0]=ipv6only=off",
+		"admin.ipFamilyPolicy=PreferDualStack",
+		"status.address=[::]",
+		"status.http.parameters[0]=ipv6only=off",
+		"cluster.address=[::]",
+		"cluster.tls.parameters[0]=ipv6only=off",
+		"ingressController.admissionWebhook.address=[::]",
 	}
 }
 
@@ -662,17 +1036,17 @@ func enterpriseDefaults() []string {
 // test cases to use these how they see fit AND clean up after themselves.
 func exposePortsDefault() []string {
 	return []string{
-		"--set", fmt.Sprintf("proxy.stream[0].containerPort=%d", DefaultTCPServicePort),
-		"--set", fmt.Sprintf("proxy.stream[0].servicePort=%d", DefaultTCPServicePort),
-		"--set", fmt.Sprintf("proxy.stream[1].containerPort=%d", DefaultTLSServicePort),
-		"--set", fmt.Sprintf("proxy.stream[1].servicePort=%d", DefaultTLSServicePort),
-		"--set", "proxy.stream[1].parameters[0]=ssl",
-		"--set", "proxy.stream[1].parameters[1]=reuseport",
-		"--set", fmt.Sprintf("udpProxy.stream[0].containerPort=%d", DefaultUDPServicePort),
-		"--set", fmt.Sprintf("udpProxy.stream[0].servicePort=%d", DefaultUDPServicePort),
-		"--set", "udpProxy.stream[0].protocol=UDP",
-		"--set", "udpProxy.stream[0].parameters[0]=udp",
-		"--set", "udpProxy.stream[0].parameters[1]=reuseport",
+		fmt.Sprintf("proxy.stream[0].containerPort=%d", DefaultTCPServicePort),
+		fmt.Sprintf("proxy.stream[0].servicePort=%d", DefaultTCPServicePort),
+		fmt.Sprintf("proxy.stream[1].containerPort=%d", DefaultTLSServicePort),
+		fmt.Sprintf("proxy.stream[1].servicePort=%d", DefaultTLSServicePort),
+		"proxy.stream[1].parameters[0]=ssl",
+		"proxy.stream[1].parameters[1]=reuseport",
+		fmt.Sprintf("udpProxy.stream[0].containerPort=%d", DefaultUDPServicePort),
+		fmt.Sprintf("udpProxy.stream[0].servicePort=%d", DefaultUDPServicePort),
+		"udpProxy.stream[0].protocol=UDP",
+		"udpProxy.stream[0].parameters[0]=udp",
+		"udpProxy.stream[0].parameters[1]=reuseport",
 	}
 }
 
@@ -0,0 +1,327 @@
+package kong
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/certmanager"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the tests")
+
+func TestValues(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		addon    *Addon
+		ipFamily clusters.IPFamily
+	}{
+		{
+			name:     "default",
+			addon:    New(),
+			ipFamily: clusters.IPv4,
+		},
+		{
+			name: "enterprise-postgres-ipv6",
+			addon: NewBuilder().
+				WithProxyEnterpriseEnabled("{}").
+				WithPostgreSQL().
+				WithProxyImage(DefaultEnterpriseImageRepo, DefaultEnterpriseImageTag).
+				Build(),
+			ipFamily: clusters.IPv6,
+		},
+		{
+			name: "dual-stack-nodeport",
+			addon: NewBuilder().
+				WithProxyServiceType(corev1.ServiceTypeNodePort).
+				WithProxyEnvVar("router_flavor", "expressions").
+				WithAdditionalValue("replicaCount", "2").
+				Build(),
+			ipFamily: clusters.Dual,
+		},
+		{
+			name: "values-precedence",
+			addon: NewBuilder().
+				WithValuesFile(filepath.Join("testdata", "overrides.yaml")).
+				WithValuesYAML([]byte(`
+admin:
+  type: NodePort
+extraObjects:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: extra
+`)).
+				WithValues(map[string]any{"replicaCount": 4, "podAnnotations": map[string]any{"example.com/team": "gateway"}}).
+				WithAdditionalValue("replicaCount", "5").
+				Build(),
+			ipFamily: clusters.IPv4,
+		},
+		{
+			name:     "gateway-api",
+			addon:    NewBuilder().WithGatewayAPI("").Build(),
+			ipFamily: clusters.IPv4,
+		},
+		{
+			name: "custom-plugins",
+			addon: NewBuilder().
+				WithProxyEnvVar("plugins", "bundled,my-other-plugin").
+				WithCustomPlugin("my-header", filepath.Join("testdata", "plugins", "my-header")).
+				Build(),
+			ipFamily: clusters.IPv4,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := tc.addon.Values(fake.NewBuilder().WithIPFamily(tc.ipFamily).MustBuild())
+			require.NoError(t, err)
+			assertGoldenValues(t, tc.name, values)
+		})
+	}
+}
+
+// kindCluster is a fake cluster which claims to be a kind cluster.
+type kindCluster struct {
+	*fake.Cluster
+}
+
+func (kindCluster) Type() clusters.Type {
+	return kind.KindClusterType
+}
+
+func TestLoadBalancerDependencies(t *testing.T) {
+	ctx := context.Background()
+	cluster := kindCluster{fake.New()}
+
+	t.Log("verifying that only a LoadBalancer Admin API Service depends on metallb on kind clusters")
+	assert.NotContains(t, New().Dependencies(ctx, cluster), metallb.AddonName)
+	assert.Contains(t, NewBuilder().WithProxyAdminServiceTypeLoadBalancer().Build().Dependencies(ctx, cluster), metallb.AddonName)
+
+	t.Log("verifying that other clusters provide LoadBalancers on their own")
+	assert.NotContains(t, NewBuilder().WithProxyAdminServiceTypeLoadBalancer().Build().Dependencies(ctx, fake.New()), metallb.AddonName)
+}
+
+func TestHybridValues(t *testing.T) {
+	cluster := fake.New()
+	addon := NewBuilder().
+		WithProxyEnterpriseEnabled("{}").
+		WithProxyServiceType(corev1.ServiceTypeNodePort).
+		WithHybridMode(2).
+		Build()
+
+	values, err := addon.Values(cluster)
+	require.NoError(t, err)
+	assertGoldenValues(t, "hybrid-control-plane", values)
+
+	values, err = addon.DataPlaneValues(cluster, 1)
+	require.NoError(t, err)
+	assertGoldenValues(t, "hybrid-data-plane", values)
+
+	_, err = addon.DataPlaneValues(cluster, 2)
+	require.ErrorContains(t, err, "has no data plane 2")
+
+	t.Log("verifying that the control plane requires a database")
+	_, err = NewBuilder().WithHybridMode(1).WithDBLess().Build().Values(cluster)
+	require.ErrorContains(t, err, "requires the postgres DB mode")
+}
+
+func assertGoldenValues(t *testing.T, name string, values map[string]any) {
+	t.Helper()
+	actual, err := yaml.Marshal(values)
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "values", name+".yaml")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, actual, 0o600))
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := New().Template(ctx)
+	require.ErrorContains(t, err, "requires a local chart archive")
+
+	manifests, err := NewBuilder().
+		WithHelmChartArchive(filepath.Join("testdata", "chart")).
+		WithProxyImage("kong", "3.9").
+		WithLogLevel("debug").
+		Build().
+		Template(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(manifests), "name: ingress-controller-kong")
+	assert.Contains(t, string(manifests), "namespace: kong-system")
+	assert.Contains(t, string(manifests), "image: kong:3.9")
+	assert.Contains(t, string(manifests), `value: "debug"`)
+
+	manifests, err = NewBuilder().
+		WithHelmChartArchive(filepath.Join("testdata", "chart")).
+		WithProxyImage("kong", "3.9").
+		WithHybridMode(2).
+		Build().
+		Template(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(manifests), "name: ingress-controller-kong")
+	assert.Contains(t, string(manifests), `value: "control_plane"`)
+	assert.Contains(t, string(manifests), "name: ingress-controller-dp-0-kong")
+	assert.Contains(t, string(manifests), "name: ingress-controller-dp-1-kong")
+	assert.Equal(t, 2, strings.Count(string(manifests), `value: "data_plane"`))
+}
+
+func TestClusterCertSecret(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+	addon := NewBuilder().WithHybridMode(1).Build()
+
+	require.NoError(t, addon.deployClusterCertSecret(ctx, cluster))
+	secret, err := cluster.Client().CoreV1().Secrets(DefaultNamespace).Get(ctx, DefaultClusterCertSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	require.NoError(t, err)
+	assert.Equal(t, "kong_clustering", cert.Leaf.Subject.CommonName)
+
+	t.Log("verifying that redeploying keeps the certificate the data planes trust")
+	require.NoError(t, addon.deployClusterCertSecret(ctx, cluster))
+	redeployed, err := cluster.Client().CoreV1().Secrets(DefaultNamespace).Get(ctx, DefaultClusterCertSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data, redeployed.Data)
+
+	require.NoError(t, addon.deleteClusterCertSecret(ctx, cluster))
+	_, err = cluster.Client().CoreV1().Secrets(DefaultNamespace).Get(ctx, DefaultClusterCertSecretName, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestClusterCertIssuer(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+	require.NoError(t, cluster.DeployAddon(ctx, fake.NewAddon(certmanager.AddonName)))
+
+	t.Log("verifying that the cluster certificate is self-signed unless an issuer is configured, even with cert-manager loaded")
+	assert.NotContains(t, NewBuilder().WithHybridMode(1).Build().Dependencies(ctx, cluster), certmanager.AddonName)
+
+	addon := NewBuilder().WithHybridMode(1).WithClusterCertIssuer("").Build()
+	assert.Equal(t, certmanager.DefaultIssuerName, addon.clusterCertIssuer)
+	assert.Contains(t, addon.Dependencies(ctx, fake.New()), certmanager.AddonName)
+
+	t.Log("verifying that the issuer is recorded in the addon's state")
+	state, err := addon.State()
+	require.NoError(t, err)
+	rehydrated, err := rehydrate(state)
+	require.NoError(t, err)
+	assert.Equal(t, certmanager.DefaultIssuerName, rehydrated.(*Addon).clusterCertIssuer)
+
+	t.Log("verifying that an issuer requires hybrid mode")
+	_, err = NewBuilder().WithClusterCertIssuer("").Build().Values(cluster)
+	require.ErrorContains(t, err, "only has a cluster certificate in hybrid mode")
+}
+
+func TestValuesDoNotAccumulate(t *testing.T) {
+	cluster := fake.New()
+	addon := NewBuilder().
+		WithProxyImage("kong", "3.9").
+		WithAdditionalValue("replicaCount", "2").
+		Build()
+
+	first, err := addon.Values(cluster)
+	require.NoError(t, err)
+	second, err := addon.Values(cluster)
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "the values are the same every time the addon is deployed")
+	assert.Equal(t, map[string]any{"repository": "kong", "tag": "3.9"}, first["image"])
+	assert.Equal(t, int64(2), first["replicaCount"])
+}
+
+func TestUpgradeRejectsImmutableChanges(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+
+	for _, tc := range []struct {
+		name   string
+		target *Addon
+		err    string
+	}{
+		{
+			name:   "namespace",
+			target: NewBuilder().WithNamespace("other").Build(),
+			err:    "the namespace of the kong addon can't be changed",
+		},
+		{
+			name:   "DB mode",
+			target: NewBuilder().WithPostgreSQL().Build(),
+			err:    "the DB mode of the kong addon can't be changed",
+		},
+		{
+			name:   "helm release name",
+			target: NewBuilder().WithHelmReleaseName("other").Build(),
+			err:    "the helm release name of the kong addon can't be changed",
+		},
+		{
+			name:   "hybrid mode",
+			target: NewBuilder().WithHybridMode(1).Build(),
+			err:    "the DB mode, number of hybrid mode data planes of the kong addon can't be changed",
+		},
+		{
+			name:   "cluster certificate issuer",
+			target: NewBuilder().WithClusterCertIssuer("").Build(),
+			err:    "the cluster certificate issuer of the kong addon can't be changed",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			deployed := NewBuilder().Build()
+			require.ErrorContains(t, deployed.Upgrade(ctx, cluster, tc.target), tc.err)
+		})
+	}
+
+	t.Run("other addon", func(t *testing.T) {
+		require.ErrorContains(t, NewBuilder().Build().Upgrade(ctx, cluster, fake.NewAddon(AddonName)), "can't upgrade the kong addon")
+	})
+}
+
+func TestValuesSources(t *testing.T) {
+	cluster := fake.New()
+
+	t.Log("verifying that later values take precedence and maps are merged")
+	addon := NewBuilder().
+		WithValuesYAML([]byte("admin:\n  type: NodePort\n")).
+		WithValues(map[string]any{"admin": map[string]any{"type": "LoadBalancer", "annotations": map[string]any{"example.com/owner": "kic"}}}).
+		Build()
+	values, err := addon.Values(cluster)
+	require.NoError(t, err)
+	admin := values["admin"].(map[string]any)
+	assert.Equal(t, "LoadBalancer", admin["type"])
+	assert.Equal(t, map[string]any{"example.com/owner": "kic"}, admin["annotations"])
+	assert.Equal(t, true, admin["enabled"], "the addon's own values are kept")
+
+	t.Log("verifying that the values are recorded in the addon's state")
+	state, err := addon.State()
+	require.NoError(t, err)
+	rehydrated, err := rehydrate(state)
+	require.NoError(t, err)
+	rehydratedValues, err := rehydrated.(*Addon).Values(cluster)
+	require.NoError(t, err)
+	assert.Equal(t, values, rehydratedValues)
+
+	t.Log("verifying that invalid values are reported")
+	_, err = NewBuilder().WithValuesFile(filepath.Join("testdata", "missing.yaml")).Build().Values(cluster)
+	require.ErrorContains(t, err, "failed to read helm values file")
+	_, err = NewBuilder().WithValuesYAML([]byte("- not a map")).Build().Values(cluster)
+	require.ErrorContains(t, err, "failed to parse helm values")
+}
@@ -0,0 +1,155 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Admin API Clients
+// -----------------------------------------------------------------------------
+
+const (
+	// DefaultAdminTLSServicePort is the port on the service at which the Kong
+	// Admin API can be reached over TLS, if it's enabled with helm values.
+	DefaultAdminTLSServicePort = 8444
+
+	// adminClientTimeout is the timeout of the requests of Admin API clients.
+	adminClientTimeout = time.Second * 90
+)
+
+// adminClients caches the Admin API clients of an addon per workspace.
+type adminClients struct {
+	lock    sync.Mutex
+	clients map[string]*kong.Client
+}
+
+// AdminClient provides a client for the Kong Admin API of the addon (see
+// ProxyAdminURL) scoped to the workspace, or to the default workspace if it's
+// empty. When RBAC is enforced the client authenticates as the enterprise
+// superadmin, and if the Admin API is only served over TLS the chart's
+// self-signed certificate is accepted. Clients are cached per workspace.
+func (a *Addon) AdminClient(ctx context.Context, cluster clusters.Cluster, workspace string) (*kong.Client, error) {
+	// the default workspace is the one without a prefix, as OSS Kong doesn't
+	// support workspaced URLs at all.
+	if workspace == "default" {
+		workspace = ""
+	}
+
+	// the lock isn't held during the Kubernetes and Admin API requests, so
+	// that slow requests don't block the other users of the addon.
+	a.adminClients.lock.Lock()
+	client, ok := a.adminClients.clients[workspace]
+	a.adminClients.lock.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	adminURL, err := a.ProxyAdminURL(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine the Kong Admin API URL: %w", err)
+	}
+
+	var adminToken string
+	if a.rbacEnforced() {
+		adminToken, err = a.superAdminPassword(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client, err = newAdminClient(adminURL, workspace, adminToken)
+	if err != nil {
+		return nil, err
+	}
+
+	a.adminClients.lock.Lock()
+	defer a.adminClients.lock.Unlock()
+	// a concurrent call may have cached a client meanwhile
+	if cached, ok := a.adminClients.clients[workspace]; ok {
+		return cached, nil
+	}
+	if a.adminClients.clients == nil {
+		a.adminClients.clients = make(map[string]*kong.Client)
+	}
+	a.adminClients.clients[workspace] = client
+	return client, nil
+}
+
+// rbacEnforced indicates whether the Admin API requires authentication, which
+// is the case for enterprise proxies with a database.
+func (a *Addon) rbacEnforced() bool {
+	return a.proxyEnterpriseEnabled && a.proxyDBMode != DBLESS
+}
+
+// superAdminPassword provides the enterprise superadmin password, which is read
+// from its Secret if the addon doesn't know it (e.g. if it was rehydrated).
+func (a *Addon) superAdminPassword(ctx context.Context, cluster clusters.Cluster) (string, error) {
+	if a.proxyEnterpriseSuperAdminPassword != "" {
+		return a.proxyEnterpriseSuperAdminPassword, nil
+	}
+	secret, err := cluster.Client().CoreV1().Secrets(a.namespace).Get(ctx, DefaultEnterpriseAdminPasswordSecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the superuser admin password secret: %w", err)
+	}
+	return string(secret.Data["password"]), nil
+}
+
+// adminTLS indicates whether the Admin API is only served over TLS, which is
+// the case if the helm values disable its HTTP listener.
+func (a *Addon) adminTLS(cluster clusters.Cluster) bool {
+	values, err := a.Values(cluster)
+	if err != nil {
+		return false
+	}
+	admin, _ := values["admin"].(map[string]any)
+	adminHTTP, _ := admin["http"].(map[string]any)
+	enabled, ok := adminHTTP["enabled"].(bool)
+	return ok && !enabled
+}
+
+// adminGet retrieves the raw response of an Admin API endpoint.
+func adminGet(ctx context.Context, client *kong.Client, endpoint string) ([]byte, error) {
+	req, err := client.NewRequest(http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	body := new(bytes.Buffer)
+	if _, err := client.Do(ctx, req, body); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// newAdminClient provides a client for the Admin API at the URL which is scoped
+// to the workspace and authenticates with the admin token unless it's empty.
+func newAdminClient(adminURL *url.URL, workspace, adminToken string) (*kong.Client, error) {
+	headers := make(http.Header)
+	if adminToken != "" {
+		headers.Set("Kong-Admin-Token", adminToken)
+	}
+
+	// the chart's certificate of TLS listeners is self-signed
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	httpClient := kong.HTTPClientWithHeaders(&http.Client{Timeout: adminClientTimeout, Transport: transport}, headers)
+
+	adminURLStr := adminURL.String()
+	client, err := kong.NewClient(&adminURLStr, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("could not build Kong Admin API client: %w", err)
+	}
+	client.SetWorkspace(workspace)
+	return client, nil
+}
@@ -0,0 +1,113 @@
+package kong
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestNewAdminClient(t *testing.T) {
+	ctx := context.Background()
+	var path, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, token = r.URL.Path, r.Header.Get("Kong-Admin-Token")
+		_, _ = w.Write([]byte(`{"data":[],"next":null}`))
+	}))
+	defer server.Close()
+	adminURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := newAdminClient(adminURL, "team-a", "secret")
+	require.NoError(t, err)
+	_, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "/team-a/services", path)
+	assert.Equal(t, "secret", token)
+
+	client, err = newAdminClient(adminURL, "", "")
+	require.NoError(t, err)
+	_, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "/services", path)
+	assert.Empty(t, token)
+}
+
+func TestAdminClientCredentials(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.NewBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: DefaultNamespace, Name: DefaultEnterpriseAdminPasswordSecretName},
+		Data:       map[string][]byte{"password": []byte("from-secret")},
+	}).MustBuild()
+
+	t.Log("verifying that RBAC is only enforced for enterprise proxies with a database")
+	assert.False(t, NewBuilder().WithProxyEnterpriseEnabled("{}").Build().rbacEnforced())
+	assert.True(t, NewBuilder().WithProxyEnterpriseEnabled("{}").WithPostgreSQL().Build().rbacEnforced())
+
+	t.Log("verifying that the superadmin password is read from its secret unless it's known")
+	password, err := NewBuilder().WithProxyEnterpriseSuperAdminPassword("known").Build().superAdminPassword(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "known", password)
+	password, err = New().superAdminPassword(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "from-secret", password)
+
+	t.Log("verifying that the Admin API is reached over TLS if its HTTP listener is disabled")
+	assert.False(t, New().adminTLS(cluster))
+	assert.True(t, NewBuilder().WithValues(map[string]any{
+		"admin": map[string]any{"http": map[string]any{"enabled": false}, "tls": map[string]any{"enabled": true}},
+	}).Build().adminTLS(cluster))
+}
+
+func TestAdminClientDoesNotBlock(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+	adminURL, err := url.Parse("http://127.0.0.1:8001")
+	require.NoError(t, err)
+	cached, err := newAdminClient(adminURL, "", "")
+	require.NoError(t, err)
+	addon := New()
+	addon.adminClients.clients = map[string]*kong.Client{"": cached}
+
+	t.Log("blocking the requests which determine the Admin API URL")
+	blocked, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	var once sync.Once
+	cluster.Client().(*k8sfake.Clientset).PrependReactor("*", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		once.Do(func() { close(blocked) })
+		<-release
+		return false, nil, nil
+	})
+	go func() {
+		_, _ = addon.AdminClient(ctx, cluster, "team-a")
+	}()
+	<-blocked
+
+	t.Log("verifying that cached clients are provided while another client is built")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client, err := addon.AdminClient(ctx, cluster, "default")
+		assert.NoError(t, err)
+		assert.Same(t, cached, client)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("AdminClient blocked on a client which is being built")
+	}
+}
@@ -2,9 +2,13 @@ package kong
 
 import (
 	"io"
+	"slices"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/certmanager"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/helm"
 )
 
 const (
@@ -24,8 +28,8 @@ type Builder struct {
 	// kubernetes and helm chart related configuration options
 	namespace       string
 	helmReleaseName string
-	deployArgs      []string
 	chartVersion    string
+	chartArchive    string
 
 	// ingress controller configuration options
 	ingressControllerDisabled bool
@@ -42,6 +46,7 @@ type Builder struct {
 	proxyServiceType                  corev1.ServiceType
 	proxyEnvVars                      map[string]string
 	proxyReadinessProbePath           string
+	customPlugins                     []customPlugin
 
 	// ports
 	httpNodePort  int
@@ -51,8 +56,25 @@ type Builder struct {
 	proxyEnterpriseEnabled            bool
 	proxyEnterpriseSuperAdminPassword string
 	proxyEnterpriseLicenseJSON        string
+
+	// enterprise RBAC entities which are created when the addon is deployed
+	workspaces []string
+	rbacRoles  []RBACRole
+	rbacUsers  []RBACUser
+
+	// hybrid mode configuration options
+	hybridDataPlanes  int
+	clusterCertIssuer string
+
+	// gatewayClassName is the name of the GatewayClass of the ingress
+	// controller, or empty if the Gateway API isn't enabled.
+	gatewayClassName string
+
+	// valuesSources are the helm values merged over the addon's own values, in
+	// the order they were configured.
+	valuesSources []valuesSource
 	// additionalValues stores values that are set during installing by helm.
-	// for each key-value pair, an argument `--set <key>=<value>` is added.
+	// each key-value pair is parsed like helm's `--set <key>=<value>` flag.
 	additionalValues map[string]string
 }
 
@@ -63,7 +85,6 @@ func NewBuilder() *Builder {
 		name:             DefaultKongAddonName,
 		namespace:        DefaultNamespace,
 		helmReleaseName:  DefaultDeploymentName,
-		deployArgs:       []string{},
 		proxyEnvVars:     make(map[string]string),
 		additionalValues: make(map[string]string),
 	}
@@ -103,8 +124,8 @@ func (b *Builder) Build() *Addon {
 
 		namespace:       b.namespace,
 		helmReleaseName: b.helmReleaseName,
-		deployArgs:      b.deployArgs,
 		chartVersion:    b.chartVersion,
+		chartArchive:    b.chartArchive,
 
 		ingressControllerDisabled: b.ingressControllerDisabled,
 		ingressControllerImage:    b.ingressControllerImage,
@@ -119,14 +140,26 @@ func (b *Builder) Build() *Addon {
 		proxyServiceType:                  b.proxyServiceType,
 		proxyEnvVars:                      b.proxyEnvVars,
 		proxyReadinessProbePath:           b.proxyReadinessProbePath,
+		customPlugins:                     slices.Clone(b.customPlugins),
 
 		proxyEnterpriseEnabled:            b.proxyEnterpriseEnabled,
 		proxyEnterpriseLicenseJSON:        b.proxyEnterpriseLicenseJSON,
 		proxyEnterpriseSuperAdminPassword: b.proxyEnterpriseSuperAdminPassword,
 
+		workspaces: slices.Clone(b.workspaces),
+		rbacRoles:  slices.Clone(b.rbacRoles),
+		rbacUsers:  slices.Clone(b.rbacUsers),
+
 		httpNodePort:  b.httpNodePort,
 		adminNodePort: b.adminNodePort,
 
+		adminClients: &adminClients{},
+
+		hybridDataPlanes:  b.hybridDataPlanes,
+		clusterCertIssuer: b.clusterCertIssuer,
+		gatewayClassName:  b.gatewayClassName,
+
+		valuesSources:    slices.Clone(b.valuesSources),
 		additionalValues: b.additionalValues,
 	}
 }
@@ -208,6 +241,61 @@ func (b *Builder) WithProxyEnvVar(name, value string) *Builder {
 	return b
 }
 
+// WithCustomPlugin loads a custom Lua plugin from a local source directory
+// (with its handler.lua and schema.lua files) into the proxy, without building
+// a custom proxy image. The Lua files of the directory and its subdirectories
+// are packaged into ConfigMaps when the addon is deployed, mounted through the
+// chart's plugins.configMaps values and enabled next to the bundled plugins
+// (KONG_PLUGINS=bundled,<name>), or next to the plugins configured with
+// WithProxyEnvVar("plugins", ...). Configuring a plugin name again replaces
+// its source directory.
+func (b *Builder) WithCustomPlugin(name, sourceDir string) *Builder {
+	b.customPlugins = slices.DeleteFunc(b.customPlugins, func(plugin customPlugin) bool {
+		return plugin.Name == name
+	})
+	b.customPlugins = append(b.customPlugins, customPlugin{Name: name, SourceDir: sourceDir})
+	return b
+}
+
+// WithHybridMode configures the resulting Addon to deploy Kong in hybrid mode:
+// a control plane release (with the ingress controller, unless it's disabled)
+// backed by PostgreSQL, and the given number (at least one) of DB-less data
+// plane releases which get their configuration from the control plane. The
+// cluster certificate they authenticate each other with is self-signed, unless
+// WithClusterCertIssuer is used.
+//
+// Each data plane has its own proxy Services, see Addon.DataPlaneProxyURLs.
+func (b *Builder) WithHybridMode(dataPlaneReplicas int) *Builder {
+	b.hybridDataPlanes = max(dataPlaneReplicas, 1)
+	b.proxyDBMode = PostgreSQL
+	return b
+}
+
+// WithClusterCertIssuer configures the resulting Addon to have the cluster
+// certificate of hybrid mode issued by the cert-manager ClusterIssuer with the
+// given name (or certmanager.DefaultIssuerName if it's empty) instead of
+// self-signing it. The Addon then depends on the certmanager addon.
+func (b *Builder) WithClusterCertIssuer(issuerName string) *Builder {
+	if issuerName == "" {
+		issuerName = certmanager.DefaultIssuerName
+	}
+	b.clusterCertIssuer = issuerName
+	return b
+}
+
+// WithGatewayAPI enables the ingress controller's Gateway API support (including
+// its alpha resources) and creates a GatewayClass with the given name (or
+// DefaultGatewayClassName if it's empty) for the controller when the addon is
+// deployed. The addon depends on the gatewayapi addon, which installs the
+// Gateway API CRDs. The ingress controller must not be disabled.
+func (b *Builder) WithGatewayAPI(className string) *Builder {
+	if className == "" {
+		className = DefaultGatewayClassName
+	}
+	b.gatewayClassName = className
+	return b
+}
+
 // -----------------------------------------------------------------------------
 // Kong Proxy Enterprise Configuration Options
 // -----------------------------------------------------------------------------
@@ -244,24 +332,88 @@ func (b *Builder) WithProxyEnterpriseSuperAdminPassword(password string) *Builde
 	return b
 }
 
+// WithWorkspaces creates workspaces of the enterprise proxy when the addon is
+// deployed. The workspaces of RBAC roles and users are created anyway.
+func (b *Builder) WithWorkspaces(workspaces ...string) *Builder {
+	b.workspaces = append(b.workspaces, workspaces...)
+	return b
+}
+
+// WithRBACRole creates an RBAC role with its endpoint permissions when the addon
+// is deployed, after its workspace. Configuring a role name again for the same
+// workspace replaces the role. RBAC entities require enterprise mode with a
+// database, which enforces RBAC.
+func (b *Builder) WithRBACRole(role RBACRole) *Builder {
+	b.rbacRoles = slices.DeleteFunc(b.rbacRoles, func(r RBACRole) bool {
+		return r.Name == role.Name && r.Workspace == role.Workspace
+	})
+	b.rbacRoles = append(b.rbacRoles, role)
+	return b
+}
+
+// WithRBACUser creates an RBAC user with its token and roles when the addon is
+// deployed, after the roles. Configuring a user name again replaces the user.
+// See Addon.AdminClientAs for a client which authenticates as the user.
+func (b *Builder) WithRBACUser(user RBACUser) *Builder {
+	b.rbacUsers = slices.DeleteFunc(b.rbacUsers, func(u RBACUser) bool {
+		return u.Name == user.Name
+	})
+	b.rbacUsers = append(b.rbacUsers, user)
+	return b
+}
+
 // WithHelmChartVersion sets the helm chart version to use for the Kong proxy.
 func (b *Builder) WithHelmChartVersion(version string) *Builder {
 	b.chartVersion = version
 	return b
 }
 
+// WithHelmChartArchive configures the addon to use a local chart archive (or
+// chart directory) instead of the chart repository, e.g. to deploy the addon
+// without access to the repository or to render it with Addon.Template.
+func (b *Builder) WithHelmChartArchive(path string) *Builder {
+	b.chartArchive = path
+	return b
+}
+
 // WithProxyReadinessProbePath sets the path to use for the proxy readiness probe.
 func (b *Builder) WithProxyReadinessProbePath(path string) *Builder {
 	b.proxyReadinessProbePath = path
 	return b
 }
 
-// WithAdditionalValue sets arbitrary value of installing by helm.
+// WithAdditionalValue sets arbitrary value of installing by helm. The name and
+// value are parsed like helm's `--set <name>=<value>` flag, and take precedence
+// over all other values (see Addon.Values).
 func (b *Builder) WithAdditionalValue(name, value string) *Builder {
 	b.additionalValues[name] = value
 	return b
 }
 
+// WithValues merges structured helm values over the addon's own values, e.g.
+// for lists or keys which are hard to escape for WithAdditionalValue. Values
+// configured later take precedence (see Addon.Values for the full order).
+func (b *Builder) WithValues(values map[string]any) *Builder {
+	b.valuesSources = append(b.valuesSources, valuesSource{values: helm.MergeValues(nil, values)})
+	return b
+}
+
+// WithValuesFile merges the helm values of a YAML file, like helm's --values
+// flag, over the addon's own values. The file is read when the addon is
+// deployed. Values configured later take precedence (see Addon.Values).
+func (b *Builder) WithValuesFile(path string) *Builder {
+	b.valuesSources = append(b.valuesSources, valuesSource{file: path})
+	return b
+}
+
+// WithValuesYAML merges helm values provided as a YAML document over the
+// addon's own values. Values configured later take precedence (see
+// Addon.Values).
+func (b *Builder) WithValuesYAML(values []byte) *Builder {
+	b.valuesSources = append(b.valuesSources, valuesSource{yaml: slices.Clone(values)})
+	return b
+}
+
 // WithHTTPNodePort sets the HTTP Nodeport.
 func (b *Builder) WithHTTPNodePort(port int) *Builder {
 	b.httpNodePort = port
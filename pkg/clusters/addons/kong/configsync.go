@@ -0,0 +1,172 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Configuration Sync
+// -----------------------------------------------------------------------------
+
+const (
+	// DefaultStatusPort is the container port of the proxy's status listener,
+	// which serves the /status endpoint of the Admin API without requiring
+	// authentication (and also on data planes, which have no Admin API).
+	DefaultStatusPort = 8100
+
+	// emptyConfigurationHash is the configuration hash which proxies report
+	// before they have loaded any configuration.
+	emptyConfigurationHash = "00000000000000000000000000000000"
+
+	// defaultConfigSyncInterval is the default amount of time between polls of
+	// the proxies' configuration hashes.
+	defaultConfigSyncInterval = time.Second
+)
+
+// ConfigSyncOptions configures how WaitForConfigSync waits for the proxies.
+type ConfigSyncOptions struct {
+	// ExpectedHash is the configuration hash which all proxy replicas have to
+	// report. If it's empty the replicas only have to report the same
+	// non-empty hash.
+	ExpectedHash string
+
+	// Interval is the amount of time between polls of the proxy replicas,
+	// one second by default.
+	Interval time.Duration
+}
+
+// WaitForConfigSync waits until every proxy pod (the pods of all the data
+// planes in hybrid mode) reports the same non-empty configuration_hash on the
+// /status endpoint, or the ExpectedHash if it's set. Unlike requests through
+// the proxy Service, which reach a single replica, this ensures that none of
+// the replicas serves a stale configuration. Proxies only report the hash in
+// DB-less mode and as hybrid mode data planes. The context provided should
+// have a timeout associated with it.
+func (a *Addon) WaitForConfigSync(ctx context.Context, cluster clusters.Cluster, opts ConfigSyncOptions) error {
+	if a.proxyDBMode != DBLESS && !a.hybrid() {
+		return fmt.Errorf("the kong addon %s doesn't report configuration hashes: only DB-less and hybrid mode proxies do", a.name)
+	}
+	interval := opts.Interval
+	if interval == 0 {
+		interval = defaultConfigSyncInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		hashes, err := a.configurationHashes(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		if configSynced(hashes, opts.ExpectedHash) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context completed while waiting for proxy configuration to sync (hashes: %s): %w", formatHashes(hashes), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// configurationHashes provides the configuration hash reported by each running
+// proxy pod, by pod name. The hash is empty if it couldn't be retrieved (e.g.
+// because the pod isn't listening yet) or if the pod has no configuration.
+func (a *Addon) configurationHashes(ctx context.Context, cluster clusters.Cluster) (map[string]string, error) {
+	serviceNames := []string{a.proxyServiceName()}
+	if a.hybrid() {
+		serviceNames = make([]string, 0, a.hybridDataPlanes)
+		for i := range a.hybridDataPlanes {
+			serviceNames = append(serviceNames, chartFullname(a.dataPlaneReleaseName(i))+"-proxy")
+		}
+	}
+
+	hashes := make(map[string]string)
+	for _, serviceName := range serviceNames {
+		service, err := cluster.Client().CoreV1().Services(a.namespace).Get(ctx, serviceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proxy service %s: %w", serviceName, err)
+		}
+		pods, err := cluster.Client().CoreV1().Pods(a.namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list proxy pods of service %s: %w", serviceName, err)
+		}
+
+		for _, pod := range pods.Items {
+			// terminating pods will never catch up with the configuration
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+				continue
+			}
+			hashes[pod.Name] = a.configurationHash(ctx, cluster, pod.Name)
+		}
+	}
+	return hashes, nil
+}
+
+// configurationHash retrieves the configuration hash of a proxy pod through
+// the API server's pod proxy, so that the pod doesn't need to be routable.
+func (a *Addon) configurationHash(ctx context.Context, cluster clusters.Cluster, pod string) string {
+	body, err := cluster.Client().CoreV1().Pods(a.namespace).
+		ProxyGet("http", pod, strconv.Itoa(DefaultStatusPort), "/status", nil).
+		DoRaw(ctx)
+	if err != nil {
+		return ""
+	}
+	var status struct {
+		ConfigurationHash string `json:"configuration_hash"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return ""
+	}
+	if status.ConfigurationHash == emptyConfigurationHash {
+		return ""
+	}
+	return status.ConfigurationHash
+}
+
+// configSynced indicates whether there are proxy pods and all of them report
+// the same non-empty hash, which is the expected hash unless that's empty.
+func configSynced(hashes map[string]string, expected string) bool {
+	if len(hashes) == 0 {
+		return false
+	}
+	for _, hash := range hashes {
+		if hash == "" || (expected != "" && hash != expected) {
+			return false
+		}
+		if expected == "" {
+			expected = hash
+		}
+	}
+	return true
+}
+
+func formatHashes(hashes map[string]string) string {
+	if len(hashes) == 0 {
+		return "no running proxy pods"
+	}
+	formatted := make([]string, 0, len(hashes))
+	for pod, hash := range hashes {
+		if hash == "" {
+			hash = "none"
+		}
+		formatted = append(formatted, pod+"="+hash)
+	}
+	sort.Strings(formatted)
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,96 @@
+package kong
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestWaitForConfigSync(t *testing.T) {
+	proxyPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: DefaultNamespace, Name: name, Labels: map[string]string{"app": "proxy"}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	cluster := fake.New(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: DefaultNamespace, Name: "ingress-controller-kong-proxy"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "proxy"}},
+		},
+		proxyPod("proxy-a", corev1.PodRunning),
+		proxyPod("proxy-b", corev1.PodRunning),
+		proxyPod("proxy-pending", corev1.PodPending),
+	)
+
+	var lock sync.Mutex
+	hashes := map[string]string{"proxy-a": "aaa", "proxy-b": emptyConfigurationHash}
+	setHash := func(pod, hash string) {
+		lock.Lock()
+		defer lock.Unlock()
+		hashes[pod] = hash
+	}
+	cluster.Client().(*k8sfake.Clientset).PrependProxyReactor("pods", func(action k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return true, statusResponse(`{"configuration_hash":"` + hashes[action.(k8stesting.ProxyGetAction).GetName()] + `"}`), nil
+	})
+
+	addon := New()
+	opts := ConfigSyncOptions{Interval: time.Millisecond * 10}
+	timeoutCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	t.Log("verifying that a replica without configuration isn't considered synced")
+	err := addon.WaitForConfigSync(timeoutCtx(), cluster, opts)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "proxy-a=aaa, proxy-b=none")
+
+	t.Log("verifying that replicas with different hashes aren't considered synced")
+	setHash("proxy-b", "bbb")
+	require.ErrorIs(t, addon.WaitForConfigSync(timeoutCtx(), cluster, opts), context.DeadlineExceeded)
+
+	t.Log("verifying that replicas with the same hash are considered synced")
+	setHash("proxy-b", "aaa")
+	require.NoError(t, addon.WaitForConfigSync(timeoutCtx(), cluster, opts))
+
+	t.Log("verifying that replicas have to report the expected hash if it's set")
+	opts.ExpectedHash = "ccc"
+	require.ErrorIs(t, addon.WaitForConfigSync(timeoutCtx(), cluster, opts), context.DeadlineExceeded)
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		setHash("proxy-a", "ccc")
+		setHash("proxy-b", "ccc")
+	}()
+	require.NoError(t, addon.WaitForConfigSync(timeoutCtx(), cluster, opts))
+
+	t.Log("verifying that proxies with a database are rejected as they don't report hashes")
+	err = NewBuilder().WithPostgreSQL().Build().WaitForConfigSync(timeoutCtx(), cluster, opts)
+	require.ErrorContains(t, err, "doesn't report configuration hashes")
+}
+
+// statusResponse is a restclient.ResponseWrapper with a fixed body.
+type statusResponse string
+
+func (r statusResponse) DoRaw(context.Context) ([]byte, error) {
+	return []byte(r), nil
+}
+
+func (r statusResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return nil, nil
+}
@@ -0,0 +1,235 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/kong/go-database-reconciler/pkg/dump"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-database-reconciler/pkg/state"
+	"github.com/kong/go-kong/kong"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Declarative Configuration
+// -----------------------------------------------------------------------------
+
+// declarativeConfigParallelism is the number of concurrent Admin API requests
+// used to sync a declarative configuration, like decK's default.
+const declarativeConfigParallelism = 10
+
+// Diff describes the changes to the entities of a workspace which would be
+// needed for the gateway to have the configuration of a declarative (decK)
+// configuration. It's empty if the gateway is configured accordingly.
+type Diff struct {
+	Creating []DiffEntity `json:"creating,omitempty"`
+	Updating []DiffEntity `json:"updating,omitempty"`
+	Deleting []DiffEntity `json:"deleting,omitempty"`
+}
+
+// DiffEntity is an entity which differs between the gateway and a declarative
+// configuration.
+type DiffEntity struct {
+	// Kind is the kind of the entity, e.g. "service" or "route".
+	Kind string `json:"kind"`
+
+	// Name is the name of the entity, or its ID if it has no name.
+	Name string `json:"name"`
+
+	// Body is the expected entity, or the existing entity if it's deleted.
+	Body any `json:"body,omitempty"`
+}
+
+// Empty indicates whether the gateway has the expected configuration.
+func (d Diff) Empty() bool {
+	return len(d.Creating) == 0 && len(d.Updating) == 0 && len(d.Deleting) == 0
+}
+
+// String lists the changes, one entity per line, for use in test failures.
+func (d Diff) String() string {
+	var out strings.Builder
+	for _, changes := range []struct {
+		action   string
+		entities []DiffEntity
+	}{
+		{"creating", d.Creating},
+		{"updating", d.Updating},
+		{"deleting", d.Deleting},
+	} {
+		for _, entity := range changes.entities {
+			fmt.Fprintf(&out, "%s %s %s\n", changes.action, entity.Kind, entity.Name)
+		}
+	}
+	return out.String()
+}
+
+// SyncDeclarativeConfig configures the gateway with a declarative (decK)
+// configuration in YAML or JSON, replacing the entities of the workspace set
+// by its _workspace field (or of the default workspace). In DB-less mode the
+// configuration is posted to the /config endpoint of the Admin API Service,
+// which only reaches one of multiple proxy replicas, and workspaces other than
+// the default one aren't supported. With a database the configuration is
+// synced with the go-database-reconciler, which creates the workspace if it
+// doesn't exist yet.
+func (a *Addon) SyncDeclarativeConfig(ctx context.Context, cluster clusters.Cluster, config []byte) error {
+	content, err := parseDeclarativeConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if a.proxyDBMode == DBLESS {
+		if !isDefaultWorkspace(content.Workspace) {
+			return fmt.Errorf("can't sync workspace %s: workspaces aren't supported in DB-less mode", content.Workspace)
+		}
+		client, err := a.AdminClient(ctx, cluster, "")
+		if err != nil {
+			return err
+		}
+		body, err := yaml.YAMLToJSON(config)
+		if err != nil {
+			return fmt.Errorf("failed to convert declarative config to JSON: %w", err)
+		}
+		if err := client.ReloadDeclarativeRawConfig(ctx, bytes.NewReader(body), false, true); err != nil {
+			return fmt.Errorf("failed to post declarative config: %w", err)
+		}
+		return nil
+	}
+
+	if !isDefaultWorkspace(content.Workspace) {
+		if err := a.ensureWorkspace(ctx, cluster, content.Workspace); err != nil {
+			return err
+		}
+	}
+	if _, err := a.solveDeclarativeConfig(ctx, cluster, content, false); err != nil {
+		return fmt.Errorf("failed to sync declarative config: %w", err)
+	}
+	return nil
+}
+
+// DiffDeclarativeConfig compares the configuration of the gateway with the
+// expected declarative (decK) configuration in YAML or JSON, for the workspace
+// set by its _workspace field (or the default workspace). The gateway isn't
+// modified, and an empty Diff indicates that it's configured exactly as
+// expected. This works in DB-less mode as well as with a database.
+func (a *Addon) DiffDeclarativeConfig(ctx context.Context, cluster clusters.Cluster, expected []byte) (Diff, error) {
+	content, err := parseDeclarativeConfig(expected)
+	if err != nil {
+		return Diff{}, err
+	}
+	if !isDefaultWorkspace(content.Workspace) && a.proxyDBMode == DBLESS {
+		return Diff{}, fmt.Errorf("can't diff workspace %s: workspaces aren't supported in DB-less mode", content.Workspace)
+	}
+
+	changes, err := a.solveDeclarativeConfig(ctx, cluster, content, true)
+	if err != nil {
+		return Diff{}, fmt.Errorf("failed to diff declarative config: %w", err)
+	}
+	return Diff{
+		Creating: diffEntities(changes.Creating),
+		Updating: diffEntities(changes.Updating),
+		Deleting: diffEntities(changes.Deleting),
+	}, nil
+}
+
+// solveDeclarativeConfig compares the current state of the content's workspace
+// with the content and applies the changes, unless it's a dry run.
+func (a *Addon) solveDeclarativeConfig(ctx context.Context, cluster clusters.Cluster, content *file.Content, dry bool) (diff.EntityChanges, error) {
+	client, err := a.AdminClient(ctx, cluster, content.Workspace)
+	if err != nil {
+		return diff.EntityChanges{}, err
+	}
+	root, err := client.Root(ctx)
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not retrieve Kong root: %w", err)
+	}
+	version, err := kong.ParseSemanticVersion(kong.VersionFromInfo(root))
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not parse Kong version: %w", err)
+	}
+
+	dumpConfig := dump.Config{}
+	rawState, err := dump.Get(ctx, client, dumpConfig)
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not retrieve config from Kong: %w", err)
+	}
+	currentState, err := state.Get(rawState)
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not build current Kong state: %w", err)
+	}
+	targetRawState, err := file.Get(ctx, content, file.RenderConfig{
+		CurrentState: currentState,
+		KongVersion:  semver.Version{Major: version.Major(), Minor: version.Minor(), Patch: version.Patch()},
+	}, dumpConfig, client)
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not render declarative config: %w", err)
+	}
+	targetState, err := state.Get(targetRawState)
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not build target Kong state: %w", err)
+	}
+
+	syncer, err := diff.NewSyncer(diff.SyncerOpts{
+		CurrentState:    currentState,
+		TargetState:     targetState,
+		KongClient:      client,
+		SilenceWarnings: true,
+		NoMaskValues:    true,
+	})
+	if err != nil {
+		return diff.EntityChanges{}, fmt.Errorf("could not create syncer: %w", err)
+	}
+	// JSON output collects the changes instead of printing them
+	_, errs, changes := syncer.Solve(ctx, declarativeConfigParallelism, dry, true)
+	if len(errs) != 0 {
+		return changes, errors.Join(errs...)
+	}
+	return changes, nil
+}
+
+// ensureWorkspace creates the workspace unless it exists already.
+func (a *Addon) ensureWorkspace(ctx context.Context, cluster clusters.Cluster, workspace string) error {
+	client, err := a.AdminClient(ctx, cluster, "")
+	if err != nil {
+		return err
+	}
+	exists, err := client.Workspaces.ExistsByName(ctx, kong.String(workspace))
+	if err != nil {
+		return fmt.Errorf("could not check whether workspace %s exists: %w", workspace, err)
+	}
+	if exists {
+		return nil
+	}
+	if _, err := client.Workspaces.Create(ctx, &kong.Workspace{Name: kong.String(workspace)}); err != nil {
+		return fmt.Errorf("could not create workspace %s: %w", workspace, err)
+	}
+	return nil
+}
+
+// parseDeclarativeConfig parses a declarative configuration in YAML or JSON.
+func parseDeclarativeConfig(config []byte) (*file.Content, error) {
+	content := new(file.Content)
+	if err := yaml.Unmarshal(config, content); err != nil {
+		return nil, fmt.Errorf("failed to parse declarative config: %w", err)
+	}
+	return content, nil
+}
+
+func isDefaultWorkspace(workspace string) bool {
+	return workspace == "" || workspace == "default"
+}
+
+func diffEntities(entities []diff.EntityState) []DiffEntity {
+	var converted []DiffEntity
+	for _, entity := range entities {
+		converted = append(converted, DiffEntity{Kind: entity.Kind, Name: entity.Name, Body: entity.Body})
+	}
+	return converted
+}
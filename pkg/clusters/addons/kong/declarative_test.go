@@ -0,0 +1,64 @@
+package kong
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestSyncDeclarativeConfigDBLess(t *testing.T) {
+	ctx := context.Background()
+	var method, path, query, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		method, path, query, body = r.Method, r.URL.Path, r.URL.RawQuery, string(content)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	adminURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client, err := newAdminClient(adminURL, "", "")
+	require.NoError(t, err)
+
+	addon := NewBuilder().WithDBLess().Build()
+	addon.adminClients.clients = map[string]*kong.Client{"": client}
+
+	t.Log("verifying that the declarative config is posted to /config as JSON")
+	require.NoError(t, addon.SyncDeclarativeConfig(ctx, fake.New(), []byte(`_format_version: "3.0"
+services:
+- name: httpbin
+  url: http://httpbin.default.svc
+`)))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/config", path)
+	assert.Equal(t, "flatten_errors=1", query)
+	assert.JSONEq(t, `{"_format_version":"3.0","services":[{"name":"httpbin","url":"http://httpbin.default.svc"}]}`, body)
+
+	t.Log("verifying that workspaces other than the default one are rejected")
+	err = addon.SyncDeclarativeConfig(ctx, fake.New(), []byte("_workspace: team-a\n"))
+	require.ErrorContains(t, err, "workspaces aren't supported in DB-less mode")
+	_, err = addon.DiffDeclarativeConfig(ctx, fake.New(), []byte("_workspace: team-a\n"))
+	require.ErrorContains(t, err, "workspaces aren't supported in DB-less mode")
+
+	t.Log("verifying that invalid declarative configs are rejected")
+	require.ErrorContains(t, addon.SyncDeclarativeConfig(ctx, fake.New(), []byte("services: {")), "failed to parse declarative config")
+}
+
+func TestDiffString(t *testing.T) {
+	assert.True(t, Diff{}.Empty())
+	diff := Diff{
+		Creating: []DiffEntity{{Kind: "service", Name: "httpbin"}, {Kind: "route", Name: "httpbin"}},
+		Deleting: []DiffEntity{{Kind: "plugin", Name: "cors"}},
+	}
+	assert.False(t, diff.Empty())
+	assert.Equal(t, "creating service httpbin\ncreating route httpbin\ndeleting plugin cors\n", diff.String())
+}
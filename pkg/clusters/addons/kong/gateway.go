@@ -0,0 +1,91 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Gateway API
+// -----------------------------------------------------------------------------
+
+const (
+	// DefaultGatewayClassName is the name of the GatewayClass which is created
+	// if WithGatewayAPI is given no name.
+	DefaultGatewayClassName = "kong"
+
+	// GatewayControllerName is the controller name which the ingress
+	// controller reconciles GatewayClasses for.
+	GatewayControllerName = "konghq.com/kic-gateway-controller"
+
+	// gatewayClassUnmanagedAnnotation marks GatewayClasses whose Gateways are
+	// reconciled by the ingress controller rather than by an operator.
+	gatewayClassUnmanagedAnnotation = "konghq.com/gatewayclass-unmanaged"
+)
+
+// GatewayClassName provides the name of the GatewayClass of the addon's ingress
+// controller, or an empty string if the Gateway API isn't enabled (see
+// Builder.WithGatewayAPI).
+func (a *Addon) GatewayClassName() string {
+	return a.gatewayClassName
+}
+
+// gatewayAPIValues provides the helm values which enable the Gateway API
+// support of the ingress controller, including the alpha resources which
+// are reconciled if the experimental CRDs are installed.
+func (a *Addon) gatewayAPIValues() []string {
+	return []string{"ingressController.env.feature_gates=GatewayAlpha=true"}
+}
+
+// deployGatewayClass creates the GatewayClass of the ingress controller, or
+// updates it if it exists already.
+func (a *Addon) deployGatewayClass(ctx context.Context, cluster clusters.Cluster) error {
+	client, err := gatewayclient.NewForConfig(cluster.Config())
+	if err != nil {
+		return err
+	}
+	gatewayClasses := client.GatewayV1().GatewayClasses()
+
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        a.gatewayClassName,
+			Annotations: map[string]string{gatewayClassUnmanagedAnnotation: "true"},
+		},
+		Spec: gatewayv1.GatewayClassSpec{
+			ControllerName: GatewayControllerName,
+		},
+	}
+	existing, err := gatewayClasses.Get(ctx, a.gatewayClassName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = gatewayClasses.Create(ctx, gatewayClass, metav1.CreateOptions{})
+	case err == nil:
+		existing.Annotations = gatewayClass.Annotations
+		existing.Spec = gatewayClass.Spec
+		_, err = gatewayClasses.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deploy GatewayClass %s: %w", a.gatewayClassName, err)
+	}
+	return nil
+}
+
+// deleteGatewayClass deletes the GatewayClass of the ingress controller.
+func (a *Addon) deleteGatewayClass(ctx context.Context, cluster clusters.Cluster) error {
+	client, err := gatewayclient.NewForConfig(cluster.Config())
+	if err != nil {
+		return err
+	}
+	err = client.GatewayV1().GatewayClasses().Delete(ctx, a.gatewayClassName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete GatewayClass %s: %w", a.gatewayClassName, err)
+	}
+	return nil
+}
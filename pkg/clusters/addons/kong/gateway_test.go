@@ -0,0 +1,38 @@
+package kong
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/gatewayapi"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestGatewayAPI(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+
+	t.Log("verifying that the Gateway API is disabled by default")
+	assert.Empty(t, New().GatewayClassName())
+	assert.NotContains(t, New().Dependencies(ctx, cluster), gatewayapi.AddonName)
+
+	t.Log("verifying that the GatewayClass name defaults and the CRDs are a dependency")
+	addon := NewBuilder().WithGatewayAPI("").Build()
+	assert.Equal(t, DefaultGatewayClassName, addon.GatewayClassName())
+	assert.Contains(t, addon.Dependencies(ctx, cluster), gatewayapi.AddonName)
+	assert.Equal(t, "kong-test", NewBuilder().WithGatewayAPI("kong-test").Build().GatewayClassName())
+
+	t.Log("verifying that the Gateway API requires the ingress controller")
+	_, err := NewBuilder().WithGatewayAPI("").WithControllerDisabled().Build().Values(cluster)
+	require.ErrorContains(t, err, "requires the ingress controller for the Gateway API")
+
+	t.Log("verifying that the GatewayClass name is recorded in the addon state")
+	state, err := addon.State()
+	require.NoError(t, err)
+	rehydrated, err := rehydrate(state)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultGatewayClassName, rehydrated.(*Addon).GatewayClassName())
+}
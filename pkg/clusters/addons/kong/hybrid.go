@@ -0,0 +1,313 @@
+package kong
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	certmanagerclient "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	cmutils "github.com/kong/kubernetes-testing-framework/pkg/utils/certmanager"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Hybrid Mode - Consts
+// -----------------------------------------------------------------------------
+
+const (
+	// DefaultClusterCertSecretName is the name of the TLS Secret holding the
+	// certificate and key which the control plane and the data planes use to
+	// authenticate each other in hybrid mode.
+	DefaultClusterCertSecretName = "kong-cluster-cert"
+
+	// DefaultClusterServicePort is the port of the control plane's cluster
+	// Service which the data planes connect to in hybrid mode.
+	DefaultClusterServicePort = 8005
+
+	// DefaultClusterTelemetryServicePort is the port of the control plane's
+	// cluster telemetry Service (enterprise only) in hybrid mode.
+	DefaultClusterTelemetryServicePort = 8006
+
+	// clusterCertCommonName is the common name of the cluster certificate, which
+	// is also the server name the data planes expect by default.
+	clusterCertCommonName = "kong_clustering"
+
+	// clusterCertDir is where the chart mounts the cluster certificate Secret.
+	clusterCertDir = "/etc/secrets/" + DefaultClusterCertSecretName
+
+	// clusterCertValidity is how long generated cluster certificates are valid.
+	clusterCertValidity = time.Hour * 24 * 365
+
+	// controlPlaneRelease is passed to values() for the control plane's chart
+	// release in hybrid mode, or for the only release otherwise.
+	controlPlaneRelease = -1
+)
+
+// -----------------------------------------------------------------------------
+// Kong Hybrid Mode - Public Methods
+// -----------------------------------------------------------------------------
+
+// ControlPlaneAdminURL provides a routable *url.URL for accessing the Admin API
+// of the control plane in hybrid mode.
+func (a *Addon) ControlPlaneAdminURL(ctx context.Context, cluster clusters.Cluster) (*url.URL, error) {
+	if !a.hybrid() {
+		return nil, fmt.Errorf("the kong addon %s isn't deployed in hybrid mode", a.name)
+	}
+	return a.ProxyAdminURL(ctx, cluster)
+}
+
+// DataPlaneProxyURLs provides routable *url.URLs for accessing the HTTP proxy
+// of each data plane in hybrid mode, in the order of their releases.
+func (a *Addon) DataPlaneProxyURLs(ctx context.Context, cluster clusters.Cluster) ([]*url.URL, error) {
+	if !a.hybrid() {
+		return nil, fmt.Errorf("the kong addon %s isn't deployed in hybrid mode", a.name)
+	}
+
+	urls := make([]*url.URL, 0, a.hybridDataPlanes)
+	for i := range a.hybridDataPlanes {
+		nsn := types.NamespacedName{Namespace: a.namespace, Name: chartFullname(a.dataPlaneReleaseName(i)) + "-proxy"}
+		urlStr, err := a.urlForService(ctx, cluster, nsn, DefaultProxyHTTPPort)
+		if err != nil {
+			return nil, err
+		}
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// DataPlaneValues provides the helm values of the chart release of the data
+// plane with the given index in hybrid mode (see Values).
+func (a *Addon) DataPlaneValues(cluster clusters.Cluster, dataPlane int) (map[string]any, error) {
+	if dataPlane < 0 || dataPlane >= a.hybridDataPlanes {
+		return nil, fmt.Errorf("the kong addon %s has no data plane %d", a.name, dataPlane)
+	}
+	return a.values(cluster.IPFamily(), dataPlane)
+}
+
+// -----------------------------------------------------------------------------
+// Kong Hybrid Mode - Private Methods
+// -----------------------------------------------------------------------------
+
+// hybrid indicates whether the addon deploys a separate control plane and data
+// planes.
+func (a *Addon) hybrid() bool {
+	return a.hybridDataPlanes > 0
+}
+
+// dataPlaneReleaseName provides the name of the chart release of a data plane.
+func (a *Addon) dataPlaneReleaseName(dataPlane int) string {
+	return fmt.Sprintf("%s-dp-%d", a.helmReleaseName, dataPlane)
+}
+
+// proxyServiceName provides the name of the proxy Service, which is the one of
+// the first data plane in hybrid mode.
+func (a *Addon) proxyServiceName() string {
+	if a.hybrid() {
+		return chartFullname(a.dataPlaneReleaseName(0)) + "-proxy"
+	}
+	return chartFullname(a.helmReleaseName) + "-proxy"
+}
+
+// udpProxyServiceName provides the name of the UDP proxy Service, which is the
+// one of the first data plane in hybrid mode.
+func (a *Addon) udpProxyServiceName() string {
+	if a.hybrid() {
+		return chartFullname(a.dataPlaneReleaseName(0)) + "-udp-proxy"
+	}
+	return chartFullname(a.helmReleaseName) + "-udp-proxy"
+}
+
+// hybridValues provides the values which configure a release as the control
+// plane or as one of the data planes.
+func (a *Addon) hybridValues(dataPlane int) []string {
+	sets := []string{
+		fmt.Sprintf("env.cluster_cert=%s/tls.crt", clusterCertDir),
+		fmt.Sprintf("env.cluster_cert_key=%s/tls.key", clusterCertDir),
+		fmt.Sprintf("secretVolumes[0]=%s", DefaultClusterCertSecretName),
+	}
+
+	controlPlane := chartFullname(a.helmReleaseName)
+	if dataPlane == controlPlaneRelease {
+		sets = append(sets,
+			"env.role=control_plane",
+			"cluster.enabled=true",
+			"cluster.tls.enabled=true",
+			"proxy.enabled=false",
+			"udpProxy.enabled=false",
+			// the controller reports the address of the first data plane in the
+			// status of the resources it configures.
+			fmt.Sprintf("ingressController.env.publish_service=%s/%s", a.namespace, a.proxyServiceName()),
+		)
+		if a.proxyEnterpriseEnabled {
+			sets = append(sets, "clustertelemetry.enabled=true", "clustertelemetry.tls.enabled=true")
+		}
+		return sets
+	}
+
+	sets = append(sets,
+		"env.role=data_plane",
+		"env.database=off",
+		fmt.Sprintf("env.lua_ssl_trusted_certificate=%s/tls.crt", clusterCertDir),
+		fmt.Sprintf("env.cluster_control_plane=%s-cluster.%s.svc:%d", controlPlane, a.namespace, DefaultClusterServicePort),
+		"admin.enabled=false",
+	)
+	if a.proxyEnterpriseEnabled {
+		sets = append(sets, fmt.Sprintf("env.cluster_telemetry_endpoint=%s-clustertelemetry.%s.svc:%d",
+			controlPlane, a.namespace, DefaultClusterTelemetryServicePort))
+	}
+	return sets
+}
+
+// deployClusterCertSecret provides the cluster certificate Secret for hybrid
+// mode, issued by the cert-manager ClusterIssuer of WithClusterCertIssuer or
+// self-signed otherwise. An existing Secret is kept, so that redeploying the
+// addon doesn't disconnect the data planes.
+func (a *Addon) deployClusterCertSecret(ctx context.Context, cluster clusters.Cluster) error {
+	_, err := cluster.Client().CoreV1().Secrets(a.namespace).Get(ctx, DefaultClusterCertSecretName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the cluster certificate secret: %w", err)
+	}
+
+	if a.clusterCertIssuer != "" {
+		cert := &certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DefaultClusterCertSecretName,
+				Namespace: a.namespace,
+			},
+			Spec: certmanagerv1.CertificateSpec{
+				SecretName: DefaultClusterCertSecretName,
+				CommonName: clusterCertCommonName,
+				Duration:   &metav1.Duration{Duration: clusterCertValidity},
+				PrivateKey: &certmanagerv1.CertificatePrivateKey{Algorithm: certmanagerv1.ECDSAKeyAlgorithm},
+				IssuerRef: cmmeta.IssuerReference{
+					Name:  a.clusterCertIssuer,
+					Kind:  "ClusterIssuer",
+					Group: "cert-manager.io",
+				},
+			},
+		}
+		if _, err := cmutils.CreateCertAndWaitForReadiness(ctx, cluster.Config(), a.namespace, cert); err != nil {
+			return fmt.Errorf("failed to issue the cluster certificate: %w", err)
+		}
+		return nil
+	}
+
+	certPEM, keyPEM, err := generateClusterCert()
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		Type: corev1.SecretTypeTLS,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultClusterCertSecretName,
+			Namespace: a.namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+	if _, err := cluster.Client().CoreV1().Secrets(a.namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the cluster certificate secret: %w", err)
+	}
+	return nil
+}
+
+// deleteClusterCertSecret deletes the cluster certificate Secret and, if it
+// was issued by cert-manager, its Certificate.
+func (a *Addon) deleteClusterCertSecret(ctx context.Context, cluster clusters.Cluster) error {
+	if a.clusterCertIssuer != "" {
+		cmc, err := certmanagerclient.NewForConfig(cluster.Config())
+		if err != nil {
+			return fmt.Errorf("failed to create a cert-manager API client: %w", err)
+		}
+		err = cmc.CertmanagerV1().Certificates(a.namespace).Delete(ctx, DefaultClusterCertSecretName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the cluster certificate: %w", err)
+		}
+	}
+
+	err := cluster.Client().CoreV1().Secrets(a.namespace).Delete(ctx, DefaultClusterCertSecretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the cluster certificate secret: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Kong Hybrid Mode - Private Functions
+// -----------------------------------------------------------------------------
+
+// chartFullname provides the name prefix of the resources of a release of the
+// kong chart, like the chart's "kong.fullname" template.
+func chartFullname(release string) string {
+	return strings.TrimSuffix(truncate(release+"-kong", 63), "-") //nolint:mnd
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length]
+	}
+	return s
+}
+
+// generateClusterCert generates a self-signed cluster certificate and key like
+// `kong hybrid gen_cert`, PEM encoded.
+func generateClusterCert() (certPEM []byte, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the cluster certificate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)) //nolint:mnd
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the cluster certificate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: clusterCertCommonName},
+		DNSNames:              []string{clusterCertCommonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(clusterCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the cluster certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode the cluster certificate key: %w", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
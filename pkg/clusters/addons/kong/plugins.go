@@ -0,0 +1,188 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Custom Plugins
+// -----------------------------------------------------------------------------
+
+// customPluginReleaseLabel labels the ConfigMaps of custom plugins with the
+// name of the chart release which loads them, so that they can be deleted
+// even if the plugins' source directories are gone.
+const customPluginReleaseLabel = "ktf.konghq.com/kong-custom-plugin-release"
+
+// invalidConfigMapNameChars matches the characters which ConfigMap (and volume)
+// names can't contain.
+var invalidConfigMapNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// customPlugin is a custom Lua plugin whose source files are read from a local
+// directory and mounted into the proxy containers through ConfigMaps.
+type customPlugin struct {
+	Name      string `json:"name"`
+	SourceDir string `json:"sourceDir"`
+}
+
+// pluginDirectory holds the Lua files of a directory of a custom plugin, which
+// are packaged into a single ConfigMap as ConfigMaps can't hold directories.
+type pluginDirectory struct {
+	// path is the path of the directory relative to the plugin's source
+	// directory, or empty for the source directory itself.
+	path  string
+	files map[string]string
+}
+
+// directories reads the Lua files of the plugin's source directory and of its
+// subdirectories. The source directory itself always comes first.
+func (p customPlugin) directories() ([]pluginDirectory, error) {
+	byPath := map[string]map[string]string{"": {}}
+	err := filepath.WalkDir(p.SourceDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".lua" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		dir, err := filepath.Rel(p.SourceDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		dir = filepath.ToSlash(dir)
+		if dir == "." {
+			dir = ""
+		}
+		if byPath[dir] == nil {
+			byPath[dir] = make(map[string]string)
+		}
+		byPath[dir][entry.Name()] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the source files of custom plugin %s: %w", p.Name, err)
+	}
+	if len(byPath[""]) == 0 {
+		return nil, fmt.Errorf("custom plugin %s has no Lua files in %s", p.Name, p.SourceDir)
+	}
+
+	directories := make([]pluginDirectory, 0, len(byPath))
+	for _, path := range slices.Sorted(maps.Keys(byPath)) {
+		directories = append(directories, pluginDirectory{path: path, files: byPath[path]})
+	}
+	return directories, nil
+}
+
+// customPluginValues provides the helm values which mount the ConfigMaps of the
+// custom plugins and enable them next to the bundled (or configured) plugins.
+func (a *Addon) customPluginValues() ([]string, error) {
+	var sets []string
+	// the custom plugins are loaded next to the configured ones, if any
+	plugins := []string{"bundled"}
+	if configured, ok := a.proxyEnvVars["plugins"]; ok {
+		plugins = plugins[:0]
+		for _, name := range strings.Split(configured, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				plugins = append(plugins, name)
+			}
+		}
+	}
+	for i, plugin := range a.customPlugins {
+		directories, err := plugin.directories()
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets,
+			fmt.Sprintf("plugins.configMaps[%d].pluginName=%s", i, plugin.Name),
+			fmt.Sprintf("plugins.configMaps[%d].name=%s", i, a.pluginConfigMapName(plugin.Name, "")),
+		)
+		// the chart mounts each subdirectory from its own ConfigMap
+		for j, directory := range directories[1:] {
+			sets = append(sets,
+				fmt.Sprintf("plugins.configMaps[%d].subdirectories[%d].name=%s", i, j, a.pluginConfigMapName(plugin.Name, directory.path)),
+				fmt.Sprintf("plugins.configMaps[%d].subdirectories[%d].path=%s", i, j, directory.path),
+			)
+		}
+		if !slices.Contains(plugins, plugin.Name) {
+			plugins = append(plugins, plugin.Name)
+		}
+	}
+	// the commas must be escaped as the values are parsed like --set flags
+	sets = append(sets, "env.plugins="+strings.Join(plugins, `\,`))
+	return sets, nil
+}
+
+// deployCustomPlugins creates or updates the ConfigMaps of the custom plugins.
+func (a *Addon) deployCustomPlugins(ctx context.Context, cluster clusters.Cluster) error {
+	configMaps := cluster.Client().CoreV1().ConfigMaps(a.namespace)
+	for _, plugin := range a.customPlugins {
+		directories, err := plugin.directories()
+		if err != nil {
+			return err
+		}
+		for _, directory := range directories {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      a.pluginConfigMapName(plugin.Name, directory.path),
+					Namespace: a.namespace,
+					Labels:    map[string]string{customPluginReleaseLabel: a.helmReleaseName},
+				},
+				Data: directory.files,
+			}
+			_, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to deploy the ConfigMap of custom plugin %s: %w", plugin.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteCustomPlugins deletes the ConfigMaps of the custom plugins.
+func (a *Addon) deleteCustomPlugins(ctx context.Context, cluster clusters.Cluster) error {
+	configMaps := cluster.Client().CoreV1().ConfigMaps(a.namespace)
+	list, err := configMaps.List(ctx, metav1.ListOptions{
+		LabelSelector: customPluginReleaseLabel + "=" + a.helmReleaseName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the ConfigMaps of custom plugins: %w", err)
+	}
+	for _, configMap := range list.Items {
+		err := configMaps.Delete(ctx, configMap.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the ConfigMap of custom plugins %s: %w", configMap.Name, err)
+		}
+	}
+	return nil
+}
+
+// pluginConfigMapName provides the name of the ConfigMap of a directory of a
+// custom plugin, which is also used as a volume name by the chart.
+func (a *Addon) pluginConfigMapName(plugin, path string) string {
+	name := fmt.Sprintf("%s-plugin-%s", a.helmReleaseName, plugin)
+	if path != "" {
+		name += "-" + path
+	}
+	name = invalidConfigMapNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.TrimSuffix(truncate(name, 63), "-") //nolint:mnd
+}
@@ -0,0 +1,67 @@
+package kong
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestCustomPluginConfigMaps(t *testing.T) {
+	ctx := context.Background()
+	sourceDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "handler.lua"), []byte("return {}"), 0o600))
+	unrelated := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: DefaultNamespace, Name: "unrelated"}}
+	cluster := fake.New(unrelated)
+	addon := NewBuilder().
+		WithCustomPlugin("my_plugin", sourceDir).
+		WithCustomPlugin("my-header", filepath.Join("testdata", "plugins", "my-header")).
+		Build()
+
+	t.Log("verifying that each plugin directory is packaged into a ConfigMap")
+	require.NoError(t, addon.deployCustomPlugins(ctx, cluster))
+	configMaps := cluster.Client().CoreV1().ConfigMaps(DefaultNamespace)
+	configMap, err := configMaps.Get(ctx, "ingress-controller-plugin-my-plugin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"handler.lua": "return {}"}, configMap.Data)
+	configMap, err = configMaps.Get(ctx, "ingress-controller-plugin-my-header-migrations", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"init.lua": "return {}\n"}, configMap.Data)
+	configMap, err = configMaps.Get(ctx, "ingress-controller-plugin-my-header", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"handler.lua", "schema.lua"}, slices.Sorted(maps.Keys(configMap.Data)))
+
+	t.Log("verifying that redeploying updates the ConfigMaps")
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "handler.lua"), []byte("return { PRIORITY = 1 }"), 0o600))
+	require.NoError(t, addon.deployCustomPlugins(ctx, cluster))
+	configMap, err = configMaps.Get(ctx, "ingress-controller-plugin-my-plugin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "return { PRIORITY = 1 }", configMap.Data["handler.lua"])
+
+	t.Log("verifying that only the ConfigMaps of the plugins are deleted")
+	require.NoError(t, addon.deleteCustomPlugins(ctx, cluster))
+	list, err := configMaps.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "unrelated", list.Items[0].Name)
+
+	t.Log("verifying that plugins without Lua files are rejected")
+	_, err = NewBuilder().WithCustomPlugin("empty", t.TempDir()).Build().Values(cluster)
+	require.ErrorContains(t, err, "custom plugin empty has no Lua files")
+
+	t.Log("verifying that the plugins are recorded in the addon state")
+	state, err := addon.State()
+	require.NoError(t, err)
+	rehydrated, err := rehydrate(state)
+	require.NoError(t, err)
+	assert.Equal(t, addon.customPlugins, rehydrated.(*Addon).customPlugins)
+}
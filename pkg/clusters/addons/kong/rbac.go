@@ -0,0 +1,318 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	pwgen "github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Enterprise RBAC
+// -----------------------------------------------------------------------------
+
+// DefaultEnterpriseRBACUserTokensSecretName is the name of the Secret which
+// holds the tokens of the RBAC users of the addon, keyed by user name.
+const DefaultEnterpriseRBACUserTokensSecretName = "kong-enterprise-rbac-user-tokens"
+
+// RBACRole is an RBAC role which is created in a workspace of an enterprise
+// proxy when the addon is deployed (see Builder.WithRBACRole).
+type RBACRole struct {
+	// Name is the name of the role.
+	Name string `json:"name"`
+
+	// Workspace is the workspace of the role, or empty for the default one.
+	Workspace string `json:"workspace,omitempty"`
+
+	// Permissions are the endpoint permissions of the role.
+	Permissions []RBACEndpointPermission `json:"permissions,omitempty"`
+}
+
+// RBACEndpointPermission allows (or, if it's negative, denies) actions on an
+// Admin API endpoint.
+type RBACEndpointPermission struct {
+	// Workspace is the workspace of the endpoint, "*" for all workspaces, or
+	// empty for the workspace of the role.
+	Workspace string `json:"workspace,omitempty"`
+
+	// Endpoint is the path of the endpoint without the workspace prefix, e.g.
+	// "/services" or "/services/*", or "*" for all endpoints.
+	Endpoint string `json:"endpoint"`
+
+	// Actions are any of "read", "create", "update" and "delete", or "*" for
+	// all of them.
+	Actions []string `json:"actions"`
+
+	// Negative denies the actions rather than allowing them.
+	Negative bool `json:"negative,omitempty"`
+}
+
+// RBACUser is an RBAC user (an admin of the Admin API) which is created in a
+// workspace of an enterprise proxy when the addon is deployed, and which
+// authenticates with a token (see Builder.WithRBACUser and
+// Addon.AdminClientAs).
+type RBACUser struct {
+	// Name is the name of the user, which is unique within the addon.
+	Name string `json:"name"`
+
+	// Workspace is the workspace of the user, or empty for the default one.
+	Workspace string `json:"workspace,omitempty"`
+
+	// Roles are the names of the roles of the user, which are roles of the
+	// user's workspace.
+	Roles []string `json:"roles,omitempty"`
+
+	// Token is the token the user authenticates with. A token is generated if
+	// it's empty. Tokens are stored in the Secret named by
+	// DefaultEnterpriseRBACUserTokensSecretName rather than in the addon state.
+	Token string `json:"-"`
+}
+
+// AdminClientAs provides a client for the Kong Admin API of the addon (see
+// ProxyAdminURL) which authenticates as one of the addon's RBAC users and is
+// scoped to the user's workspace, e.g. to test how the ingress controller
+// behaves with restricted permissions. Unlike AdminClient the clients aren't
+// cached.
+func (a *Addon) AdminClientAs(ctx context.Context, cluster clusters.Cluster, user string) (*kong.Client, error) {
+	index := slices.IndexFunc(a.rbacUsers, func(u RBACUser) bool {
+		return u.Name == user
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("the kong addon %s has no RBAC user %s", a.name, user)
+	}
+	token, err := a.rbacUserToken(ctx, cluster, a.rbacUsers[index])
+	if err != nil {
+		return nil, err
+	}
+
+	adminURL, err := a.ProxyAdminURL(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine the Kong Admin API URL: %w", err)
+	}
+	return newAdminClient(adminURL, a.rbacUsers[index].Workspace, token)
+}
+
+// provisionsRBAC indicates whether the addon creates workspaces, roles or
+// users when it's deployed.
+func (a *Addon) provisionsRBAC() bool {
+	return len(a.workspaces) > 0 || len(a.rbacRoles) > 0 || len(a.rbacUsers) > 0
+}
+
+// sameRBAC indicates whether the target addon creates the same RBAC entities
+// as the deployed addon. RBAC user tokens are only compared if both addons know
+// them, as rehydrated addons don't.
+func (a *Addon) sameRBAC(target *Addon) bool {
+	return slices.Equal(a.workspaces, target.workspaces) &&
+		reflect.DeepEqual(a.rbacRoles, target.rbacRoles) &&
+		slices.EqualFunc(a.rbacUsers, target.rbacUsers, func(deployed, user RBACUser) bool {
+			return deployed.Name == user.Name &&
+				deployed.Workspace == user.Workspace &&
+				slices.Equal(deployed.Roles, user.Roles) &&
+				(user.Token == "" || deployed.Token == "" || deployed.Token == user.Token)
+		})
+}
+
+// rbacUserToken provides the token of an RBAC user, which is read from the
+// tokens Secret if the addon doesn't know it (e.g. if it was rehydrated).
+func (a *Addon) rbacUserToken(ctx context.Context, cluster clusters.Cluster, user RBACUser) (string, error) {
+	if user.Token != "" {
+		return user.Token, nil
+	}
+	secret, err := cluster.Client().CoreV1().Secrets(a.namespace).Get(ctx, DefaultEnterpriseRBACUserTokensSecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the RBAC user tokens secret: %w", err)
+	}
+	token, ok := secret.Data[user.Name]
+	if !ok {
+		return "", fmt.Errorf("the RBAC user tokens secret has no token for user %s", user.Name)
+	}
+	return string(token), nil
+}
+
+// deployRBACUserTokensSecret generates the tokens of the RBAC users which have
+// none, and stores all the tokens in a Secret.
+func (a *Addon) deployRBACUserTokensSecret(ctx context.Context, cluster clusters.Cluster) error {
+	data := make(map[string][]byte, len(a.rbacUsers))
+	for i := range a.rbacUsers {
+		if a.rbacUsers[i].Token == "" {
+			token, err := pwgen.Generate(secretMinLength, secretMinNumeric, secretMinSymbols, secretNoUpper, secretAllowRepeat)
+			if err != nil {
+				return fmt.Errorf("failed to generate a token for RBAC user %s: %w", a.rbacUsers[i].Name, err)
+			}
+			a.rbacUsers[i].Token = token
+		}
+		data[a.rbacUsers[i].Name] = []byte(a.rbacUsers[i].Token)
+	}
+
+	secret := &corev1.Secret{
+		Type: corev1.SecretTypeOpaque,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultEnterpriseRBACUserTokensSecretName,
+			Namespace: a.namespace,
+		},
+		Data: data,
+	}
+	if _, err := cluster.Client().CoreV1().Secrets(a.namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the RBAC user tokens secret: %w", err)
+	}
+	return nil
+}
+
+// deleteRBACUserTokensSecret deletes the Secret of the RBAC user tokens.
+func (a *Addon) deleteRBACUserTokensSecret(ctx context.Context, cluster clusters.Cluster) error {
+	err := cluster.Client().CoreV1().Secrets(a.namespace).Delete(ctx, DefaultEnterpriseRBACUserTokensSecretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the RBAC user tokens secret: %w", err)
+	}
+	return nil
+}
+
+// provisionRBAC waits for the Admin API and creates the workspaces, then the
+// roles with their permissions, and then the users with their tokens and
+// roles. Roles and users which exist already are left as they are.
+func (a *Addon) provisionRBAC(ctx context.Context, cluster clusters.Cluster) error {
+	if err := a.waitForAdminAPI(ctx, cluster); err != nil {
+		return err
+	}
+
+	for _, workspace := range a.rbacWorkspaces() {
+		if err := a.ensureWorkspace(ctx, cluster, workspace); err != nil {
+			return err
+		}
+	}
+	for _, role := range a.rbacRoles {
+		if err := a.ensureRBACRole(ctx, cluster, role); err != nil {
+			return err
+		}
+	}
+	for _, user := range a.rbacUsers {
+		if err := a.ensureRBACUser(ctx, cluster, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rbacWorkspaces provides the workspaces which are created explicitly, and the
+// ones of the roles and users, except for the default workspace.
+func (a *Addon) rbacWorkspaces() []string {
+	workspaces := slices.Clone(a.workspaces)
+	for _, role := range a.rbacRoles {
+		workspaces = append(workspaces, role.Workspace)
+	}
+	for _, user := range a.rbacUsers {
+		workspaces = append(workspaces, user.Workspace)
+	}
+	workspaces = slices.DeleteFunc(workspaces, isDefaultWorkspace)
+	slices.Sort(workspaces)
+	return slices.Compact(workspaces)
+}
+
+// ensureRBACRole creates the role with its endpoint permissions unless it
+// exists already.
+func (a *Addon) ensureRBACRole(ctx context.Context, cluster clusters.Cluster, role RBACRole) error {
+	client, err := a.AdminClient(ctx, cluster, role.Workspace)
+	if err != nil {
+		return err
+	}
+	_, err = client.RBACRoles.Get(ctx, kong.String(role.Name))
+	if err == nil {
+		return nil
+	}
+	if !kong.IsNotFoundErr(err) {
+		return fmt.Errorf("could not check whether RBAC role %s exists: %w", role.Name, err)
+	}
+
+	created, err := client.RBACRoles.Create(ctx, &kong.RBACRole{Name: kong.String(role.Name)})
+	if err != nil {
+		return fmt.Errorf("could not create RBAC role %s: %w", role.Name, err)
+	}
+	for _, permission := range role.Permissions {
+		workspace := permission.Workspace
+		if workspace == "" {
+			workspace = role.Workspace
+		}
+		if workspace == "" {
+			workspace = "default"
+		}
+		_, err := client.RBACEndpointPermissions.Create(ctx, &kong.RBACEndpointPermission{
+			Role:      created,
+			Workspace: kong.String(workspace),
+			Endpoint:  kong.String(permission.Endpoint),
+			Actions:   kong.StringSlice(permission.Actions...),
+			Negative:  kong.Bool(permission.Negative),
+		})
+		if err != nil {
+			return fmt.Errorf("could not create the permission of RBAC role %s for endpoint %s: %w", role.Name, permission.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+// ensureRBACUser creates the user with its token and roles unless it exists
+// already.
+func (a *Addon) ensureRBACUser(ctx context.Context, cluster clusters.Cluster, user RBACUser) error {
+	client, err := a.AdminClient(ctx, cluster, user.Workspace)
+	if err != nil {
+		return err
+	}
+	_, err = client.RBACUsers.Get(ctx, kong.String(user.Name))
+	if err == nil {
+		return nil
+	}
+	if !kong.IsNotFoundErr(err) {
+		return fmt.Errorf("could not check whether RBAC user %s exists: %w", user.Name, err)
+	}
+
+	token, err := a.rbacUserToken(ctx, cluster, user)
+	if err != nil {
+		return err
+	}
+	_, err = client.RBACUsers.Create(ctx, &kong.RBACUser{
+		Name:      kong.String(user.Name),
+		UserToken: kong.String(token),
+		Enabled:   kong.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create RBAC user %s: %w", user.Name, err)
+	}
+	if len(user.Roles) == 0 {
+		return nil
+	}
+	roles := make([]*kong.RBACRole, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, &kong.RBACRole{Name: kong.String(role)})
+	}
+	if _, err := client.RBACUsers.AddRoles(ctx, kong.String(user.Name), roles); err != nil {
+		return fmt.Errorf("could not add the roles of RBAC user %s: %w", user.Name, err)
+	}
+	return nil
+}
+
+// waitForAdminAPI waits until the Admin API of the deployed addon responds.
+func (a *Addon) waitForAdminAPI(ctx context.Context, cluster clusters.Cluster) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		client, err := a.AdminClient(ctx, cluster, "")
+		if err == nil {
+			if _, err = client.Root(ctx); err == nil {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context completed while waiting for the Kong Admin API (last error: %v): %w", err, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package kong
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestProvisionRBAC(t *testing.T) {
+	ctx := context.Background()
+	var requests []string
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		request := r.Method + " " + r.URL.Path
+		requests = append(requests, request)
+		bodies[request] = string(content)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version":"3.4.1.0-enterprise-edition"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"reader-id","name":"reader"}`))
+		}
+	}))
+	defer server.Close()
+	adminURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	addon := NewBuilder().
+		WithProxyEnterpriseEnabled("{}").
+		WithPostgreSQL().
+		WithWorkspaces("team-b", "default").
+		WithRBACRole(RBACRole{
+			Name:        "reader",
+			Workspace:   "team-a",
+			Permissions: []RBACEndpointPermission{{Endpoint: "/services", Actions: []string{"read"}}},
+		}).
+		WithRBACUser(RBACUser{Name: "alice", Workspace: "team-a", Roles: []string{"reader"}, Token: "alice-token"}).
+		Build()
+	addon.adminClients.clients = make(map[string]*kong.Client)
+	for _, workspace := range []string{"", "team-a"} {
+		addon.adminClients.clients[workspace], err = newAdminClient(adminURL, workspace, "password")
+		require.NoError(t, err)
+	}
+
+	t.Log("verifying that the workspaces, then the roles and then the users are created")
+	require.NoError(t, addon.provisionRBAC(ctx, fake.New()))
+	assert.Equal(t, []string{
+		"GET /",
+		"GET /team-a/workspaces/team-a",
+		"POST /workspaces",
+		"GET /team-b/workspaces/team-b",
+		"POST /workspaces",
+		"GET /team-a/rbac/roles/reader",
+		"POST /team-a/rbac/roles",
+		"POST /team-a/rbac/roles/reader-id/endpoints",
+		"GET /team-a/rbac/users/alice",
+		"POST /team-a/rbac/users",
+		"POST /team-a/rbac/users/alice/roles",
+	}, requests)
+	assert.JSONEq(t, `{"workspace":"team-a","endpoint":"/services","actions":"read","negative":false}`,
+		bodies["POST /team-a/rbac/roles/reader-id/endpoints"])
+	assert.JSONEq(t, `{"name":"alice","user_token":"alice-token","enabled":true}`, bodies["POST /team-a/rbac/users"])
+	assert.JSONEq(t, `{"name_or_id":"alice","roles":"reader"}`, bodies["POST /team-a/rbac/users/alice/roles"])
+}
+
+func TestRBACUserTokens(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+
+	t.Log("verifying that RBAC entities require enterprise mode with a database")
+	_, err := NewBuilder().WithWorkspaces("team-a").Build().Values(cluster)
+	require.ErrorContains(t, err, "requires enterprise mode with a database for RBAC entities")
+	_, err = NewBuilder().WithProxyEnterpriseEnabled("{}").WithPostgreSQL().WithWorkspaces("team-a").Build().Values(cluster)
+	require.NoError(t, err)
+
+	t.Log("verifying that missing tokens are generated and stored in a secret")
+	addon := NewBuilder().
+		WithProxyEnterpriseEnabled("{}").
+		WithPostgreSQL().
+		WithRBACUser(RBACUser{Name: "alice", Token: "alice-token"}).
+		WithRBACUser(RBACUser{Name: "bob"}).
+		Build()
+	require.NoError(t, addon.deployRBACUserTokensSecret(ctx, cluster))
+	secret, err := cluster.Client().CoreV1().Secrets(DefaultNamespace).Get(ctx, DefaultEnterpriseRBACUserTokensSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "alice-token", string(secret.Data["alice"]))
+	assert.Len(t, secret.Data["bob"], secretMinLength)
+
+	t.Log("verifying that rehydrated addons read the tokens from the secret")
+	state, err := addon.State()
+	require.NoError(t, err)
+	rehydrated, err := rehydrate(state)
+	require.NoError(t, err)
+	bob := rehydrated.(*Addon).rbacUsers[1]
+	assert.Empty(t, bob.Token)
+	token, err := rehydrated.(*Addon).rbacUserToken(ctx, cluster, bob)
+	require.NoError(t, err)
+	assert.Equal(t, string(secret.Data["bob"]), token)
+
+	t.Log("verifying that the RBAC entities can't be changed by an upgrade")
+	require.NoError(t, rehydrated.(*Addon).validateUpgrade(NewBuilder().WithProxyEnterpriseEnabled("{}").WithPostgreSQL().
+		WithRBACUser(RBACUser{Name: "alice", Token: "alice-token"}).WithRBACUser(RBACUser{Name: "bob"}).Build()))
+	err = rehydrated.(*Addon).validateUpgrade(NewBuilder().WithProxyEnterpriseEnabled("{}").WithPostgreSQL().
+		WithRBACUser(RBACUser{Name: "alice", Roles: []string{"reader"}}).WithRBACUser(RBACUser{Name: "bob"}).Build())
+	require.ErrorContains(t, err, "RBAC entities")
+	err = addon.validateUpgrade(NewBuilder().WithProxyEnterpriseEnabled("{}").WithPostgreSQL().
+		WithRBACUser(RBACUser{Name: "alice", Token: "other-token"}).WithRBACUser(RBACUser{Name: "bob"}).Build())
+	require.ErrorContains(t, err, "RBAC entities")
+
+	t.Log("verifying that clients can only be provided for the addon's users")
+	_, err = addon.AdminClientAs(ctx, cluster, "carol")
+	require.ErrorContains(t, err, "has no RBAC user carol")
+}
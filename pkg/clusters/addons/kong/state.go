@@ -0,0 +1,140 @@
+package kong
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the
+// cluster. The enterprise license, the superadmin password, the RBAC user tokens
+// and the image pull secret are left out: they're stored in Secrets in the
+// addon's namespace.
+type addonState struct {
+	Namespace       string `json:"namespace,omitempty"`
+	HelmReleaseName string `json:"helmReleaseName,omitempty"`
+
+	IngressControllerDisabled bool   `json:"ingressControllerDisabled,omitempty"`
+	IngressControllerImage    string `json:"ingressControllerImage,omitempty"`
+	IngressControllerImageTag string `json:"ingressControllerImageTag,omitempty"`
+
+	ProxyAdminServiceTypeLoadBalancer bool               `json:"proxyAdminServiceTypeLoadBalancer,omitempty"`
+	ProxyDBMode                       DBMode             `json:"proxyDBMode,omitempty"`
+	ProxyImage                        string             `json:"proxyImage,omitempty"`
+	ProxyImageTag                     string             `json:"proxyImageTag,omitempty"`
+	ProxyLogLevel                     string             `json:"proxyLogLevel,omitempty"`
+	ProxyServiceType                  corev1.ServiceType `json:"proxyServiceType,omitempty"`
+	ProxyEnvVars                      map[string]string  `json:"proxyEnvVars,omitempty"`
+	ProxyReadinessProbePath           string             `json:"proxyReadinessProbePath,omitempty"`
+	CustomPlugins                     []customPlugin     `json:"customPlugins,omitempty"`
+
+	HTTPNodePort  int `json:"httpNodePort,omitempty"`
+	AdminNodePort int `json:"adminNodePort,omitempty"`
+
+	ProxyEnterpriseEnabled bool              `json:"proxyEnterpriseEnabled,omitempty"`
+	Workspaces             []string          `json:"workspaces,omitempty"`
+	RBACRoles              []RBACRole        `json:"rbacRoles,omitempty"`
+	RBACUsers              []RBACUser        `json:"rbacUsers,omitempty"`
+	HybridDataPlanes       int               `json:"hybridDataPlanes,omitempty"`
+	ClusterCertIssuer      string            `json:"clusterCertIssuer,omitempty"`
+	GatewayClassName       string            `json:"gatewayClassName,omitempty"`
+	Values                 map[string]any    `json:"values,omitempty"`
+	AdditionalValues       map[string]string `json:"additionalValues,omitempty"`
+}
+
+// State provides the state of the addon, its version is the chart version. The
+// values of WithValues, WithValuesFile and WithValuesYAML are recorded merged.
+func (a *Addon) State() (clusters.AddonState, error) {
+	values, err := a.userValues()
+	if err != nil {
+		return clusters.AddonState{}, err
+	}
+
+	return clusters.NewAddonState(a.Name(), AddonName, a.chartVersion, addonState{
+		Namespace:                         a.namespace,
+		HelmReleaseName:                   a.helmReleaseName,
+		IngressControllerDisabled:         a.ingressControllerDisabled,
+		IngressControllerImage:            a.ingressControllerImage,
+		IngressControllerImageTag:         a.ingressControllerImageTag,
+		ProxyAdminServiceTypeLoadBalancer: a.proxyAdminServiceTypeLoadBalancer,
+		ProxyDBMode:                       a.proxyDBMode,
+		ProxyImage:                        a.proxyImage,
+		ProxyImageTag:                     a.proxyImageTag,
+		ProxyLogLevel:                     a.proxyLogLevel,
+		ProxyServiceType:                  a.proxyServiceType,
+		ProxyEnvVars:                      a.proxyEnvVars,
+		ProxyReadinessProbePath:           a.proxyReadinessProbePath,
+		CustomPlugins:                     a.customPlugins,
+		HTTPNodePort:                      a.httpNodePort,
+		AdminNodePort:                     a.adminNodePort,
+		ProxyEnterpriseEnabled:            a.proxyEnterpriseEnabled,
+		Workspaces:                        a.workspaces,
+		RBACRoles:                         a.rbacRoles,
+		RBACUsers:                         a.rbacUsers,
+		HybridDataPlanes:                  a.hybridDataPlanes,
+		ClusterCertIssuer:                 a.clusterCertIssuer,
+		GatewayClassName:                  a.gatewayClassName,
+		Values:                            values,
+		AdditionalValues:                  a.additionalValues,
+	})
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	var valuesSources []valuesSource
+	if opts.Values != nil {
+		valuesSources = append(valuesSources, valuesSource{values: opts.Values})
+	}
+
+	return &Addon{
+		logger:       &logrus.Logger{Out: io.Discard},
+		name:         string(s.Name),
+		adminClients: &adminClients{},
+
+		namespace:       opts.Namespace,
+		helmReleaseName: opts.HelmReleaseName,
+		chartVersion:    s.Version,
+
+		ingressControllerDisabled: opts.IngressControllerDisabled,
+		ingressControllerImage:    opts.IngressControllerImage,
+		ingressControllerImageTag: opts.IngressControllerImageTag,
+
+		proxyAdminServiceTypeLoadBalancer: opts.ProxyAdminServiceTypeLoadBalancer,
+		proxyDBMode:                       opts.ProxyDBMode,
+		proxyImage:                        opts.ProxyImage,
+		proxyImageTag:                     opts.ProxyImageTag,
+		proxyLogLevel:                     opts.ProxyLogLevel,
+		proxyServiceType:                  opts.ProxyServiceType,
+		proxyEnvVars:                      opts.ProxyEnvVars,
+		proxyReadinessProbePath:           opts.ProxyReadinessProbePath,
+		customPlugins:                     opts.CustomPlugins,
+
+		httpNodePort:  opts.HTTPNodePort,
+		adminNodePort: opts.AdminNodePort,
+
+		proxyEnterpriseEnabled: opts.ProxyEnterpriseEnabled,
+		workspaces:             opts.Workspaces,
+		rbacRoles:              opts.RBACRoles,
+		rbacUsers:              opts.RBACUsers,
+		hybridDataPlanes:       opts.HybridDataPlanes,
+		clusterCertIssuer:      opts.ClusterCertIssuer,
+		gatewayClassName:       opts.GatewayClassName,
+		valuesSources:          valuesSources,
+		additionalValues:       opts.AdditionalValues,
+	}, nil
+}
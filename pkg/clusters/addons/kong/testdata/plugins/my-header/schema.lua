@@ -0,0 +1,16 @@
+local typedefs = require "kong.db.schema.typedefs"
+
+return {
+  name = "my-header",
+  fields = {
+    { protocols = typedefs.protocols_http },
+    { config = {
+        type = "record",
+        fields = {
+          { header_name = { type = "string", default = "X-My-Header" } },
+          { header_value = { type = "string", default = "loaded" } },
+        },
+      },
+    },
+  },
+}
@@ -0,0 +1,64 @@
+package kong
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/helm"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Addon - Helm Values
+// -----------------------------------------------------------------------------
+
+// valuesSource is a set of helm values provided with WithValues, WithValuesFile
+// or WithValuesYAML. Files and YAML documents are only read when the values are
+// needed, so that errors are returned by Deploy rather than by the Builder.
+type valuesSource struct {
+	values map[string]any
+	file   string
+	yaml   []byte
+}
+
+func (s valuesSource) load() (map[string]any, error) {
+	switch {
+	case s.values != nil:
+		return s.values, nil
+	case s.file != "":
+		content, err := os.ReadFile(s.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read helm values file: %w", err)
+		}
+		values, err := parseValuesYAML(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid helm values file %s: %w", s.file, err)
+		}
+		return values, nil
+	default:
+		return parseValuesYAML(s.yaml)
+	}
+}
+
+func parseValuesYAML(content []byte) (map[string]any, error) {
+	values := make(map[string]any)
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse helm values: %w", err)
+	}
+	return values, nil
+}
+
+// userValues merges the values of all the sources, in the order they were
+// configured with the Builder.
+func (a *Addon) userValues() (map[string]any, error) {
+	merged := make(map[string]any)
+	for _, source := range a.valuesSources {
+		values, err := source.load()
+		if err != nil {
+			return nil, err
+		}
+		merged = helm.MergeValues(merged, values)
+	}
+	return merged, nil
+}
@@ -66,6 +66,10 @@ func getArgo(cluster clusters.Cluster) (*argocd.Addon, error) {
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	// wait for dependency addons to be ready first
 	if err := clusters.WaitForAddonDependencies(ctx, cluster, a); err != nil {
 		return fmt.Errorf("failure waiting for addon dependencies: %w", err)
@@ -105,7 +109,8 @@ func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.
 	}
 
 	if deployment.Status.AvailableReplicas != *deployment.Spec.Replicas {
-		return []runtime.Object{deployment}, false, nil
+		waitForObjects := []runtime.Object{deployment}
+		return waitForObjects, false, clusters.CheckForFailures(ctx, cluster, a.Name(), waitForObjects)
 	}
 
 	return nil, true, nil
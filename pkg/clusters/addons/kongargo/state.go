@@ -0,0 +1,46 @@
+package kongargo
+
+import (
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kong Argo Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the cluster.
+type addonState struct {
+	Namespace string `json:"namespace,omitempty"`
+	Project   string `json:"project,omitempty"`
+	Release   string `json:"release,omitempty"`
+	AppName   string `json:"appName,omitempty"`
+}
+
+// State provides the state of the addon, its version is the Kong chart version.
+func (a *Addon) State() (clusters.AddonState, error) {
+	return clusters.NewAddonState(a.Name(), AddonName, a.version, addonState{
+		Namespace: a.namespace,
+		Project:   a.project,
+		Release:   a.release,
+		AppName:   a.appName,
+	})
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+	return &Addon{
+		name:      string(s.Name),
+		namespace: opts.Namespace,
+		version:   s.Version,
+		project:   opts.Project,
+		release:   opts.Release,
+		appName:   opts.AppName,
+	}, nil
+}
@@ -1,24 +1,24 @@
 package kuma
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"io"
-	"os"
-	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/sirupsen/logrus"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/strvals"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kong/kubernetes-testing-framework/internal/retry"
 	"github.com/kong/kubernetes-testing-framework/internal/utils"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/helm"
 )
 
 // -----------------------------------------------------------------------------
@@ -93,7 +93,7 @@ func EnableMeshForNamespace(ctx context.Context, cluster clusters.Cluster, name
 			namespace.Labels["kuma.io/sidecar-injection"] = "enabled"
 			_, err = cluster.Client().CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
 			if err != nil {
-				if errors.IsConflict(err) {
+				if apierrors.IsConflict(err) {
 					// if there's a conflict then an update happened since we pulled the namespace,
 					// simply pull and try again.
 					select {
@@ -123,62 +123,57 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	// wait for dependency addons to be ready first
 	if err := clusters.WaitForAddonDependencies(ctx, cluster, a); err != nil {
 		return fmt.Errorf("failure waiting for addon dependencies: %w", err)
 	}
 
-	// generate a temporary kubeconfig since we're going to be using the helm CLI
-	kubeconfig, err := clusters.TempKubeconfig(cluster)
+	helmClient, err := helm.NewClient(cluster.Config(), Namespace, a.logger.Debugf)
 	if err != nil {
 		return err
 	}
-	defer os.Remove(kubeconfig.Name())
-
-	// ensure the repo exists
-	stderr := new(bytes.Buffer)
-	cmd := exec.CommandContext(ctx, "helm", "--kubeconfig", kubeconfig.Name(), "repo", "add", "--force-update", "kuma", KumaHelmRepo) //nolint:gosec
-	cmd.Stdout = io.Discard
-	cmd.Stderr = stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %w", stderr.String(), err)
-	}
 
-	// ensure all repos are up to date
-	stderr = new(bytes.Buffer)
-	cmd = exec.CommandContext(ctx, "helm", "--kubeconfig", kubeconfig.Name(), "repo", "update") //nolint:gosec
-	cmd.Stdout = io.Discard
-	cmd.Stderr = stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %w", stderr.String(), err)
+	// compile the helm installation values
+	values := make(map[string]any)
+	for name, value := range a.additionalValues {
+		if err := strvals.ParseInto(fmt.Sprintf("%s=%s", name, value), values); err != nil {
+			return fmt.Errorf("failed to parse helm value %s: %w", name, err)
+		}
 	}
 
-	// if the dbmode is postgres, set several related values
-	args := []string{"--kubeconfig", kubeconfig.Name(), "install", DefaultReleaseName, "kuma/kuma"}
-
-	if a.version != nil {
-		args = append(args, "--version", a.version.String())
+	if err := clusters.CreateNamespace(ctx, cluster, Namespace); err != nil {
+		return err
 	}
 
-	// compile the helm installation values
-	args = append(args, "--create-namespace", "--namespace", Namespace)
-
-	for name, value := range a.additionalValues {
-		args = append(args, "--set", fmt.Sprintf("%s=%s", name, value))
+	chart := helm.Chart{RepoURL: KumaHelmRepo, Name: "kuma"}
+	if a.version != nil {
+		chart.Version = a.version.String()
 	}
 
-	a.logger.Debugf("helm install arguments: %+v", args)
+	a.logger.Debugf("helm install values: %+v", values)
 
-	// Sometimes running helm install fails. Just in case this happens, retry.
-	err = retry.
-		Command("helm", args...).
-		DoWithErrorHandling(ctx, func(err error, _, stderr *bytes.Buffer) error {
+	// Sometimes installing the chart fails. Just in case this happens, retry.
+	err = retry.Do(ctx, func() error {
+		err := helmClient.Install(ctx, DefaultReleaseName, chart, values, helm.Options{})
+		var statusErr *helm.ReleaseStatusError
+		if !errors.As(err, &statusErr) {
+			return err
+		}
+		switch statusErr.Status {
+		case release.StatusDeployed:
 			// ignore if addon is already deployed
-			if strings.Contains(stderr.String(), "cannot re-use") {
-				return nil
-			}
-			return fmt.Errorf("%s: %w", stderr, err)
-		})
+			return nil
+		case release.StatusFailed:
+			// the failed release of a previous attempt is kept for diagnosis
+			return helmClient.Upgrade(ctx, DefaultReleaseName, chart, values, helm.Options{})
+		default:
+			return err
+		}
+	})
 	if err != nil {
 		return err
 	}
@@ -192,28 +187,18 @@ func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
 	return nil
 }
 
-func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
-	// generate a temporary kubeconfig since we're going to be using the helm CLI
-	kubeconfig, err := clusters.TempKubeconfig(cluster)
+func (a *Addon) Delete(_ context.Context, cluster clusters.Cluster) error {
+	helmClient, err := helm.NewClient(cluster.Config(), Namespace, a.logger.Debugf)
 	if err != nil {
 		return err
 	}
-	defer os.Remove(kubeconfig.Name())
 
 	// delete the chart release from the cluster
-	stderr := new(bytes.Buffer)
-	cmd := exec.CommandContext(ctx, "helm", "--kubeconfig", kubeconfig.Name(), "uninstall", DefaultReleaseName, "--namespace", Namespace) //nolint:gosec
-	cmd.Stdout = io.Discard
-	cmd.Stderr = stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %w", stderr.String(), err)
-	}
-
-	return nil
+	return helmClient.Uninstall(DefaultReleaseName)
 }
 
 func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) (waitForObjects []runtime.Object, ready bool, err error) {
-	return utils.IsNamespaceAvailable(ctx, cluster, Namespace)
+	return utils.IsNamespaceAvailable(ctx, cluster, a.Name(), Namespace)
 }
 
 func (a *Addon) DumpDiagnostics(context.Context, clusters.Cluster) (map[string][]byte, error) {
@@ -0,0 +1,59 @@
+package kuma
+
+import (
+	"io"
+
+	"github.com/blang/semver/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kuma Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the cluster.
+type addonState struct {
+	MTLSEnabled      bool              `json:"mtlsEnabled,omitempty"`
+	AdditionalValues map[string]string `json:"additionalValues,omitempty"`
+}
+
+// State provides the state of the addon, its version is the chart version.
+func (a *Addon) State() (clusters.AddonState, error) {
+	var version string
+	if a.version != nil {
+		version = a.version.String()
+	}
+	return clusters.NewAddonState(a.Name(), AddonName, version, addonState{
+		MTLSEnabled:      a.mtlsEnabled,
+		AdditionalValues: a.additionalValues,
+	})
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	a := &Addon{
+		name:   string(s.Name),
+		logger: &logrus.Logger{Out: io.Discard},
+
+		mtlsEnabled:      opts.MTLSEnabled,
+		additionalValues: opts.AdditionalValues,
+	}
+	if s.Version != "" {
+		version, err := semver.Parse(s.Version)
+		if err != nil {
+			return nil, err
+		}
+		a.version = &version
+	}
+	return a, nil
+}
@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
 )
 
@@ -46,6 +47,10 @@ func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
 		if err := a.loadIntoKind(ctx, cluster); err != nil {
 			return err
 		}
+	case k3d.K3dClusterType:
+		if err := a.loadIntoK3d(ctx, cluster); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("loadimage addon is not supported by cluster type '%v'", cluster.Type())
 	}
@@ -55,7 +60,7 @@ func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
 
 func (a *Addon) Delete(_ context.Context, cluster clusters.Cluster) error {
 	switch ctype := cluster.Type(); ctype {
-	case kind.KindClusterType:
+	case kind.KindClusterType, k3d.K3dClusterType:
 		// per https://github.com/kubernetes-sigs/kind/issues/658 this is basically impossible
 		// we lie here, because we want to mask this error. not deleting an image from KIND is benign:
 		// you either don't use it after (in which case you shouldn't care that it's still present) or
@@ -33,3 +33,27 @@ func (a *Addon) loadIntoKind(ctx context.Context, cluster clusters.Cluster) erro
 	a.loaded = true
 	return nil
 }
+
+func (a *Addon) loadIntoK3d(ctx context.Context, cluster clusters.Cluster) error {
+	if len(a.images) == 0 {
+		return fmt.Errorf("no images provided")
+	}
+
+	deployArgs := []string{
+		"image", "import",
+		"--cluster", cluster.Name(),
+	}
+
+	deployArgs = append(deployArgs, a.images...)
+
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, "k3d", deployArgs...)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", stderr.String(), err)
+	}
+	a.loaded = true
+	return nil
+}
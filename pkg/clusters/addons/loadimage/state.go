@@ -0,0 +1,35 @@
+package loadimage
+
+import (
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// LoadImage Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the cluster.
+type addonState struct {
+	Images []string `json:"images,omitempty"`
+}
+
+// State provides the state of the addon.
+func (a *Addon) State() (clusters.AddonState, error) {
+	return clusters.NewAddonState(a.Name(), AddonName, "", addonState{
+		Images: a.images,
+	})
+}
+
+// rehydrate rebuilds the addon, which is only recorded once its images were
+// loaded successfully.
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+	return &Addon{images: opts.Images, loaded: true}, nil
+}
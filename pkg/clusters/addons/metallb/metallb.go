@@ -24,6 +24,7 @@ import (
 
 	"github.com/kong/kubernetes-testing-framework/internal/retry"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
 	"github.com/kong/kubernetes-testing-framework/pkg/utils/docker"
 	"github.com/kong/kubernetes-testing-framework/pkg/utils/kubernetes/kubectl"
@@ -78,16 +79,26 @@ func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.A
 }
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
-	if cluster.Type() != kind.KindClusterType {
-		return fmt.Errorf("the metallb addon is currently only supported on %s clusters", kind.KindClusterType)
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
 	}
 
-	return a.deployMetallbForKindCluster(ctx, cluster, kind.DefaultKindDockerNetwork)
+	switch ctype := cluster.Type(); ctype {
+	case kind.KindClusterType:
+		return a.deployMetallbForDockerCluster(ctx, cluster, docker.GetKindContainerID(cluster.Name()), kind.DefaultKindDockerNetwork)
+	case k3d.K3dClusterType:
+		return a.deployMetallbForDockerCluster(ctx, cluster, docker.GetK3dContainerID(cluster.Name()), docker.GetK3dNetwork(cluster.Name()))
+	default:
+		return fmt.Errorf("the metallb addon is currently only supported on %s and %s clusters: %w", kind.KindClusterType, k3d.K3dClusterType, clusters.ErrUnsupportedClusterType)
+	}
 }
 
 func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
-	if cluster.Type() != kind.KindClusterType {
-		return fmt.Errorf("the metallb addon is currently only supported on %s clusters", kind.KindClusterType)
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+	if ctype := cluster.Type(); ctype != kind.KindClusterType && ctype != k3d.K3dClusterType {
+		return fmt.Errorf("the metallb addon is currently only supported on %s and %s clusters: %w", kind.KindClusterType, k3d.K3dClusterType, clusters.ErrUnsupportedClusterType)
 	}
 
 	dynamicClient, err := dynamic.NewForConfig(cluster.Config())
@@ -127,7 +138,8 @@ func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) ([]runtime.
 	}
 
 	if deployment.Status.AvailableReplicas != *deployment.Spec.Replicas {
-		return []runtime.Object{deployment}, false, nil
+		waitForObjects := []runtime.Object{deployment}
+		return waitForObjects, false, clusters.CheckForFailures(ctx, cluster, a.Name(), waitForObjects)
 	}
 
 	return nil, true, nil
@@ -151,8 +163,9 @@ var (
 // Private Functions
 // -----------------------------------------------------------------------------
 
-// deployMetallbForKindCluster deploys Metallb to the given Kind cluster using the Docker network provided for LoadBalancer IPs.
-func (a *Addon) deployMetallbForKindCluster(ctx context.Context, cluster clusters.Cluster, dockerNetwork string) error {
+// deployMetallbForDockerCluster deploys Metallb to the given Docker backed cluster (e.g. kind, k3d) using the
+// Docker network the provided node container is attached to for LoadBalancer IPs.
+func (a *Addon) deployMetallbForDockerCluster(ctx context.Context, cluster clusters.Cluster, containerID, dockerNetwork string) error {
 	// ensure the namespace for metallb is created
 	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: DefaultNamespace}}
 	if _, err := cluster.Client().CoreV1().Namespaces().Create(ctx, &ns, metav1.CreateOptions{}); err != nil {
@@ -169,7 +182,7 @@ func (a *Addon) deployMetallbForKindCluster(ctx context.Context, cluster cluster
 
 	// create an ip address pool
 	if !a.disablePoolCreation {
-		if err := createIPAddressPool(ctx, cluster, dockerNetwork); err != nil {
+		if err := createIPAddressPool(ctx, cluster, containerID, dockerNetwork); err != nil {
 			return err
 		}
 	}
@@ -201,17 +214,28 @@ func (a *Addon) deployMetallbForKindCluster(ctx context.Context, cluster cluster
 	return nil
 }
 
-func createIPAddressPool(ctx context.Context, cluster clusters.Cluster, dockerNetwork string) error {
+func createIPAddressPool(ctx context.Context, cluster clusters.Cluster, containerID, dockerNetwork string) error {
 	// get an IP range for the docker container network to use for MetalLB
 	// this returns addresses based on the _Docker network_ the cluster runs on, not the cluster itself. this may,
 	// for example, return IPv4 addresses even for an IPv6-only cluster. although unsupported addresses will be listed
 	// in the IPAddressPool, speaker will not actually assign them if they are not compatible with the cluster network.
-	network, network6, err := docker.GetDockerContainerIPNetwork(docker.GetKindContainerID(cluster.Name()), dockerNetwork)
+	network, network6, err := docker.GetDockerContainerIPNetwork(containerID, dockerNetwork)
 	if err != nil {
 		return err
 	}
-	ipStart, ipEnd := getIPRangeForMetallb(*network)
-	ip6Start, ip6End := getIPRangeForMetallb(*network6)
+	// dual-stack services get one address per family, so the pool must cover both.
+	if cluster.IPFamily() == clusters.Dual && (network == nil || network6 == nil) {
+		return fmt.Errorf("dual-stack cluster %s requires docker network %s to have both IPv4 and IPv6 subnets", cluster.Name(), dockerNetwork)
+	}
+	// not every Docker network has both families enabled (e.g. k3d networks are IPv4 only by default).
+	addresses := make([]string, 0, 2) //nolint:mnd
+	for _, n := range []*net.IPNet{network, network6} {
+		if n == nil {
+			continue
+		}
+		start, end := getIPRangeForMetallb(*n)
+		addresses = append(addresses, fmt.Sprintf("%s-%s", start, end))
+	}
 
 	dynamicClient, err := dynamic.NewForConfig(cluster.Config())
 	if err != nil {
@@ -233,10 +257,7 @@ func createIPAddressPool(ctx context.Context, cluster clusters.Cluster, dockerNe
 					"name": addressPoolName,
 				},
 				"spec": map[string]interface{}{
-					"addresses": []string{
-						fmt.Sprintf("%s-%s", ipStart, ipEnd),
-						fmt.Sprintf("%s-%s", ip6Start, ip6End),
-					},
+					"addresses": addresses,
 				},
 			},
 		}, metav1.CreateOptions{})
@@ -142,9 +142,13 @@ const (
 )
 
 func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
+	if err := clusters.RequireWorkloads(cluster, a); err != nil {
+		return err
+	}
+
 	// currently this addon can _only_ work on a kind cluster
 	if _, ok := cluster.(*kind.Cluster); !ok {
-		return fmt.Errorf("the registry addon is currently only supported on kind clusters")
+		return fmt.Errorf("the registry addon is currently only supported on kind clusters: %w", clusters.ErrUnsupportedClusterType)
 	}
 
 	// wait for dependency addons to be ready first
@@ -355,10 +359,27 @@ func (a *Addon) Deploy(ctx context.Context, cluster clusters.Cluster) error {
 	}
 	a.certificatePEM = crtPEM
 
+	// every node may need to pull images from the registry, so each node container
+	// needs to trust its certificate.
+	containerIDs, err := dockerutils.GetKindNodeContainerIDs(ctx, cluster.Name())
+	if err != nil {
+		return fmt.Errorf("failed to list kind node containers: %w", err)
+	}
+	for _, containerID := range containerIDs {
+		if err := a.trustCertificateOnNode(ctx, containerID, loadBalancerAddress); err != nil {
+			return fmt.Errorf("failed to configure node %s to trust the registry: %w", containerID, err)
+		}
+	}
+
+	return nil
+}
+
+// trustCertificateOnNode configures containerd in the given kind node container
+// to trust the registry's certificate.
+func (a *Addon) trustCertificateOnNode(ctx context.Context, containerID, loadBalancerAddress string) error {
 	// write the certificate to a tar archive, as needed for the docker client when
 	// copying files to containers.
-	containerID := dockerutils.GetKindContainerID(cluster.Name())
-	if err := dockerutils.WriteFileToContainer(ctx, containerID, registryCertPath, 0o644, crtPEM); err != nil { //nolint:mnd
+	if err := dockerutils.WriteFileToContainer(ctx, containerID, registryCertPath, 0o644, a.certificatePEM); err != nil { //nolint:mnd
 		return fmt.Errorf("failed to copy certificate to kind container: %w", err)
 	}
 
@@ -449,7 +470,7 @@ func (a *Addon) Delete(ctx context.Context, cluster clusters.Cluster) error {
 }
 
 func (a *Addon) Ready(ctx context.Context, cluster clusters.Cluster) (waitForObjects []runtime.Object, ready bool, err error) {
-	return utils.IsNamespaceAvailable(ctx, cluster, Namespace)
+	return utils.IsNamespaceAvailable(ctx, cluster, a.Name(), Namespace)
 }
 
 func (a *Addon) DumpDiagnostics(context.Context, clusters.Cluster) (map[string][]byte, error) {
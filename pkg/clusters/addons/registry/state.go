@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"github.com/blang/semver/v4"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Registry Addon - State
+// -----------------------------------------------------------------------------
+
+func init() { //nolint:gochecknoinits
+	clusters.RegisterAddonType(AddonName, rehydrate)
+}
+
+// addonState holds the builder options of the addon which are recorded in the
+// cluster, along with the addresses and certificate of the registry and the
+// names of the objects which Delete() removes.
+type addonState struct {
+	ServiceTypeLoadBalancer bool `json:"serviceTypeLoadBalancer,omitempty"`
+
+	CertificatePEM      []byte `json:"certificatePEM,omitempty"`
+	ClusterIP           string `json:"clusterIP,omitempty"`
+	LoadBalancerAddress string `json:"loadBalancerAddress,omitempty"`
+
+	DeploymentName  string `json:"deploymentName,omitempty"`
+	ServiceName     string `json:"serviceName,omitempty"`
+	CertificateName string `json:"certificateName,omitempty"`
+	CertSecretName  string `json:"certSecretName,omitempty"`
+	PVCName         string `json:"pvcName,omitempty"`
+}
+
+// State provides the state of the addon, its version is the registry image tag.
+func (a *Addon) State() (clusters.AddonState, error) {
+	var version string
+	if a.registryVersion != nil {
+		version = a.registryVersion.String()
+	}
+	return clusters.NewAddonState(a.Name(), AddonName, version, addonState{
+		ServiceTypeLoadBalancer: a.serviceTypeLoadBalancer,
+		CertificatePEM:          a.certificatePEM,
+		ClusterIP:               a.clusterIP,
+		LoadBalancerAddress:     a.loadBalancerAddress,
+		DeploymentName:          a.deploymentName,
+		ServiceName:             a.serviceName,
+		CertificateName:         a.certificateName,
+		CertSecretName:          a.certSecretName,
+		PVCName:                 a.pvcName,
+	})
+}
+
+func rehydrate(s clusters.AddonState) (clusters.Addon, error) {
+	var opts addonState
+	if err := s.DecodeOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	a := &Addon{
+		name:                    string(s.Name),
+		serviceTypeLoadBalancer: opts.ServiceTypeLoadBalancer,
+
+		certificatePEM:      opts.CertificatePEM,
+		clusterIP:           opts.ClusterIP,
+		loadBalancerAddress: opts.LoadBalancerAddress,
+
+		deploymentName:  opts.DeploymentName,
+		serviceName:     opts.ServiceName,
+		certificateName: opts.CertificateName,
+		certSecretName:  opts.CertSecretName,
+		pvcName:         opts.PVCName,
+	}
+	if s.Version != "" {
+		version, err := semver.Parse(s.Version)
+		if err != nil {
+			return nil, err
+		}
+		a.registryVersion = &version
+	}
+	return a, nil
+}
@@ -0,0 +1,57 @@
+package clusters_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+// fixedAddon is an addon which can't be upgraded.
+type fixedAddon struct {
+	clusters.Addon
+}
+
+func TestUpgradeAddon(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("upgradable", func(t *testing.T) {
+		cluster := fake.New()
+		addon := fake.NewAddon("test")
+		require.NoError(t, cluster.DeployAddon(ctx, addon))
+
+		require.NoError(t, clusters.UpgradeAddon(ctx, cluster, addon, fake.NewAddon("test")))
+		assert.Equal(t, 1, addon.UpgradeCalls(), "the deployed addon is upgraded")
+	})
+
+	t.Run("failed upgrade", func(t *testing.T) {
+		cluster := fake.New()
+		upgradeErr := errors.New("upgrade failed")
+		addon := fake.NewAddon("test").WithUpgradeError(upgradeErr)
+		require.NoError(t, cluster.DeployAddon(ctx, addon))
+
+		require.ErrorIs(t, clusters.UpgradeAddon(ctx, cluster, addon, fake.NewAddon("test")), upgradeErr)
+	})
+
+	t.Run("not upgradable", func(t *testing.T) {
+		cluster := fake.New()
+		addon := fixedAddon{fake.NewAddon("test")}
+		require.NoError(t, cluster.DeployAddon(ctx, addon))
+
+		require.ErrorIs(t, clusters.UpgradeAddon(ctx, cluster, addon, fake.NewAddon("test")), clusters.ErrAddonNotUpgradable)
+	})
+
+	t.Run("different addon", func(t *testing.T) {
+		cluster := fake.New()
+		addon := fake.NewAddon("test")
+		require.NoError(t, cluster.DeployAddon(ctx, addon))
+
+		require.ErrorContains(t, clusters.UpgradeAddon(ctx, cluster, addon, fake.NewAddon("other")), "can't upgrade addon test to addon other")
+		assert.Zero(t, addon.UpgradeCalls())
+	})
+}
@@ -109,6 +109,12 @@ func (c *Cleaner) Cleanup(ctx context.Context) error {
 			}
 
 			defer w.Stop()
+
+			// the namespace may have been deleted before the watch started
+			if _, err := namespaceClient.Get(ctx, namespace.Name, metav1.GetOptions{}); errors.IsNotFound(err) {
+				return nil
+			}
+
 			for {
 				select {
 				case event := <-w.ResultChan():
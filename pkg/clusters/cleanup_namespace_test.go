@@ -0,0 +1,32 @@
+package clusters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestCleanerDeletesNamespaces(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cleanup"}}
+	cluster := fake.New(namespace)
+	cleaner := clusters.NewCleaner(cluster, scheme.Scheme)
+	cleaner.AddNamespace(namespace)
+
+	t.Log("verifying that namespaces which are gone before the watch starts don't block the cleanup")
+	require.NoError(t, cleaner.Cleanup(ctx))
+	_, err := cluster.Client().CoreV1().Namespaces().Get(ctx, namespace.Name, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the namespace is deleted")
+}
@@ -37,8 +37,8 @@ type Cluster interface {
 	// Version indicates the Kubernetes server version of the cluster.
 	Version() (semver.Version, error)
 
-	// Client is the configured *kubernetes.Clientset which can be used to access the Cluster's API
-	Client() *kubernetes.Clientset
+	// Client is the configured kubernetes.Interface which can be used to access the Cluster's API
+	Client() kubernetes.Interface
 
 	// Config provides the *rest.Config for the cluster which is convenient for initiating custom kubernetes.Clientsets.
 	Config() *rest.Config
@@ -67,6 +67,14 @@ type Cluster interface {
 	IPFamily() IPFamily
 }
 
+// WorkloadSupporter is an optional interface for Cluster implementations which
+// may not be able to run workloads, such as control plane only clusters.
+// Clusters which don't implement it are expected to be able to run workloads.
+type WorkloadSupporter interface {
+	// SupportsWorkloads indicates whether Pods can be scheduled and run on the cluster.
+	SupportsWorkloads() bool
+}
+
 type Builder interface {
 	Build(ctx context.Context) (Cluster, error)
 }
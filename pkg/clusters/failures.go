@@ -0,0 +1,182 @@
+package clusters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// -----------------------------------------------------------------------------
+// Public Types - Addon Failures
+// -----------------------------------------------------------------------------
+
+// ErrAddonFailed indicates that an addon reached a state it's not expected to
+// recover from (e.g. an image which can't be pulled), so there's no point in
+// waiting for it to become ready.
+type ErrAddonFailed struct {
+	// Addon is the name of the failed addon.
+	Addon AddonName
+
+	// Object identifies the failed object, e.g. "Deployment kong/proxy".
+	Object string
+
+	// Reason explains the failure, e.g. "container proxy: ImagePullBackOff".
+	Reason string
+}
+
+func (e *ErrAddonFailed) Error() string {
+	return fmt.Sprintf("addon %s failed: %s: %s", e.Addon, e.Object, e.Reason)
+}
+
+// FailurePolicy configures which states of an addon's objects are considered
+// unrecoverable by CheckForFailures.
+type FailurePolicy struct {
+	// Disabled turns failure detection off: addons are waited for until
+	// they're ready or the context is done.
+	Disabled bool
+
+	// MaxImagePullBackOff is how long after a Pod started its containers
+	// may be in ImagePullBackOff before it's considered failed. Images which
+	// can't be pulled aren't considered failures when it's 0, e.g. for images
+	// which will be loaded into the cluster later on. Invalid image names are
+	// always failures.
+	MaxImagePullBackOff time.Duration
+
+	// MaxRestarts is the number of restarts after which a container in
+	// CrashLoopBackOff is considered failed. Crash loops aren't considered
+	// failures when it's 0, as some of them heal (e.g. while waiting for a
+	// dependency).
+	MaxRestarts int32
+}
+
+// DefaultFailurePolicy is the FailurePolicy used when none was provided with
+// WithFailurePolicy.
+var DefaultFailurePolicy = FailurePolicy{
+	// the kubelet retries pulls with a backoff capped at 5 minutes, so this
+	// leaves room for a few retries of pulls which fail temporarily.
+	MaxImagePullBackOff: 5 * time.Minute, //nolint:mnd
+	MaxRestarts:         5,               //nolint:mnd
+}
+
+// -----------------------------------------------------------------------------
+// Public Functions - Addon Failures
+// -----------------------------------------------------------------------------
+
+// WithFailurePolicy provides a context which configures the FailurePolicy
+// used by CheckForFailures (and thus by addons' Ready methods).
+func WithFailurePolicy(ctx context.Context, policy FailurePolicy) context.Context {
+	return context.WithValue(ctx, failurePolicyKey{}, policy)
+}
+
+// FailurePolicyFromContext provides the FailurePolicy configured with
+// WithFailurePolicy, or DefaultFailurePolicy.
+func FailurePolicyFromContext(ctx context.Context) FailurePolicy {
+	if policy, ok := ctx.Value(failurePolicyKey{}).(FailurePolicy); ok {
+		return policy
+	}
+	return DefaultFailurePolicy
+}
+
+// CheckForFailures classifies the objects an addon is waiting for, returning
+// an *ErrAddonFailed as soon as one of them is in a state which is considered
+// unrecoverable according to the context's FailurePolicy: containers which
+// can't pull their image for too long, containers in CrashLoopBackOff too often and Jobs
+// which exceeded their backoff limit. The Pods of Deployments, DaemonSets and
+// StatefulSets are checked as well.
+func CheckForFailures(ctx context.Context, cluster Cluster, addon AddonName, objects []runtime.Object) error {
+	policy := FailurePolicyFromContext(ctx)
+	if policy.Disabled {
+		return nil
+	}
+
+	for _, obj := range objects {
+		var selector *metav1.LabelSelector
+		var namespace, object string
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			selector, namespace, object = o.Spec.Selector, o.Namespace, fmt.Sprintf("Deployment %s/%s", o.Namespace, o.Name)
+		case *appsv1.DaemonSet:
+			selector, namespace, object = o.Spec.Selector, o.Namespace, fmt.Sprintf("DaemonSet %s/%s", o.Namespace, o.Name)
+		case *appsv1.StatefulSet:
+			selector, namespace, object = o.Spec.Selector, o.Namespace, fmt.Sprintf("StatefulSet %s/%s", o.Namespace, o.Name)
+		case *corev1.Pod:
+			if reason := policy.podFailure(o); reason != "" {
+				return &ErrAddonFailed{Addon: addon, Object: fmt.Sprintf("Pod %s/%s", o.Namespace, o.Name), Reason: reason}
+			}
+		case *batchv1.Job:
+			if reason := jobFailure(o); reason != "" {
+				return &ErrAddonFailed{Addon: addon, Object: fmt.Sprintf("Job %s/%s", o.Namespace, o.Name), Reason: reason}
+			}
+		}
+		if selector == nil {
+			continue
+		}
+
+		pods, err := selectPods(ctx, cluster, namespace, selector)
+		if err != nil {
+			return fmt.Errorf("failed to list pods of %s: %w", object, err)
+		}
+		for i := range pods {
+			if reason := policy.podFailure(&pods[i]); reason != "" {
+				return &ErrAddonFailed{Addon: addon, Object: object, Reason: reason}
+			}
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Private - Addon Failures
+// -----------------------------------------------------------------------------
+
+type failurePolicyKey struct{}
+
+// podFailure provides the reason the Pod failed unrecoverably, if it did.
+func (p FailurePolicy) podFailure(pod *corev1.Pod) string {
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		waiting := status.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		switch waiting.Reason {
+		case "InvalidImageName", "ErrImageNeverPull":
+			return fmt.Sprintf("container %s: %s", status.Name, waiting.Reason)
+		case "ImagePullBackOff":
+			if p.MaxImagePullBackOff > 0 && podAge(pod) >= p.MaxImagePullBackOff {
+				return fmt.Sprintf("container %s: ImagePullBackOff for more than %s", status.Name, p.MaxImagePullBackOff)
+			}
+		case "CrashLoopBackOff":
+			if p.MaxRestarts > 0 && status.RestartCount >= p.MaxRestarts {
+				return fmt.Sprintf("container %s: CrashLoopBackOff after %d restarts", status.Name, status.RestartCount)
+			}
+		}
+	}
+	return ""
+}
+
+// podAge provides how long ago the Pod started, or was created if it hasn't
+// started yet.
+func podAge(pod *corev1.Pod) time.Duration {
+	if pod.Status.StartTime != nil {
+		return time.Since(pod.Status.StartTime.Time)
+	}
+	return time.Since(pod.CreationTimestamp.Time)
+}
+
+// jobFailure provides the reason the Job failed, if it did.
+func jobFailure(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package clusters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestCheckForFailures(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "proxy"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "proxy"}},
+		},
+	}
+	podWaiting := func(reason string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "proxy-abc", Labels: map[string]string{"app": "proxy"}},
+			Status: corev1.PodStatus{
+				StartTime: &metav1.Time{Time: time.Now()},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "proxy",
+					RestartCount: restarts,
+					State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+				}},
+			},
+		}
+	}
+	startedAgo := func(pod *corev1.Pod, age time.Duration) *corev1.Pod {
+		pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-age)}
+		return pod
+	}
+	failedJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "migrations"},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		policy   *clusters.FailurePolicy
+		existing []runtime.Object
+		objects  []runtime.Object
+		expected *clusters.ErrAddonFailed
+	}{
+		{
+			name:     "recent image pull backoff",
+			existing: []runtime.Object{podWaiting("ImagePullBackOff", 0)},
+			objects:  []runtime.Object{deployment},
+		},
+		{
+			name:     "image pull backoff past the time limit",
+			existing: []runtime.Object{startedAgo(podWaiting("ImagePullBackOff", 0), 10*time.Minute)},
+			objects:  []runtime.Object{deployment},
+			expected: &clusters.ErrAddonFailed{Addon: "kong", Object: "Deployment kong/proxy", Reason: "container proxy: ImagePullBackOff for more than 5m0s"},
+		},
+		{
+			name:     "image pull backoff with time limit disabled",
+			policy:   &clusters.FailurePolicy{},
+			existing: []runtime.Object{startedAgo(podWaiting("ImagePullBackOff", 0), time.Hour)},
+			objects:  []runtime.Object{deployment},
+		},
+		{
+			name:     "invalid image name",
+			existing: []runtime.Object{podWaiting("InvalidImageName", 0)},
+			objects:  []runtime.Object{deployment},
+			expected: &clusters.ErrAddonFailed{Addon: "kong", Object: "Deployment kong/proxy", Reason: "container proxy: InvalidImageName"},
+		},
+		{
+			name:     "crash loop below the restart limit",
+			existing: []runtime.Object{podWaiting("CrashLoopBackOff", 2)},
+			objects:  []runtime.Object{deployment},
+		},
+		{
+			name:     "crash loop past the restart limit",
+			existing: []runtime.Object{podWaiting("CrashLoopBackOff", 5)},
+			objects:  []runtime.Object{deployment},
+			expected: &clusters.ErrAddonFailed{Addon: "kong", Object: "Deployment kong/proxy", Reason: "container proxy: CrashLoopBackOff after 5 restarts"},
+		},
+		{
+			name:     "crash loop with restart limit disabled",
+			policy:   &clusters.FailurePolicy{},
+			existing: []runtime.Object{podWaiting("CrashLoopBackOff", 100)},
+			objects:  []runtime.Object{deployment},
+		},
+		{
+			name:     "still creating",
+			existing: []runtime.Object{podWaiting("ContainerCreating", 0)},
+			objects:  []runtime.Object{deployment},
+		},
+		{
+			name:     "job exceeded its backoff limit",
+			objects:  []runtime.Object{failedJob},
+			expected: &clusters.ErrAddonFailed{Addon: "kong", Object: "Job kong/migrations", Reason: "BackoffLimitExceeded: Job has reached the specified backoff limit"},
+		},
+		{
+			name:    "detection disabled",
+			policy:  &clusters.FailurePolicy{Disabled: true},
+			objects: []runtime.Object{podWaiting("InvalidImageName", 0), failedJob},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.policy != nil {
+				ctx = clusters.WithFailurePolicy(ctx, *tc.policy)
+			}
+			cluster := fake.New(tc.existing...)
+
+			err := clusters.CheckForFailures(ctx, cluster, "kong", tc.objects)
+			if tc.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			var addonErr *clusters.ErrAddonFailed
+			require.ErrorAs(t, err, &addonErr)
+			assert.Equal(t, tc.expected, addonErr)
+		})
+	}
+}
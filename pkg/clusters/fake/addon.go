@@ -0,0 +1,160 @@
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Fake Addon
+// -----------------------------------------------------------------------------
+
+// ReadyResult is a scripted outcome of a call to Addon.Ready().
+type ReadyResult struct {
+	WaitingForObjects []runtime.Object
+	Ready             bool
+	Err               error
+}
+
+// Addon is a clusters.Addon implementation which deploys nothing and reports
+// scripted results, for unit testing code which manages addons.
+type Addon struct {
+	name         clusters.AddonName
+	dependencies []clusters.AddonName
+	deployErr    error
+	deleteErr    error
+	upgradeErr   error
+	readyResults []ReadyResult
+	diagnostics  map[string][]byte
+
+	l            sync.Mutex
+	deployCalls  int
+	deleteCalls  int
+	upgradeCalls int
+	readyCalls   int
+}
+
+// NewAddon provides a new fake *Addon with the given name which deploys
+// successfully and is immediately ready.
+func NewAddon(name clusters.AddonName) *Addon {
+	return &Addon{name: name}
+}
+
+// WithDependencies configures the addons this addon reports as dependencies.
+func (a *Addon) WithDependencies(dependencies ...clusters.AddonName) *Addon {
+	a.dependencies = append(a.dependencies, dependencies...)
+	return a
+}
+
+// WithDeployError configures the error returned by Deploy().
+func (a *Addon) WithDeployError(err error) *Addon {
+	a.deployErr = err
+	return a
+}
+
+// WithDeleteError configures the error returned by Delete().
+func (a *Addon) WithDeleteError(err error) *Addon {
+	a.deleteErr = err
+	return a
+}
+
+// WithUpgradeError configures the error returned by Upgrade().
+func (a *Addon) WithUpgradeError(err error) *Addon {
+	a.upgradeErr = err
+	return a
+}
+
+// WithReadyResults scripts the results of consecutive calls to Ready(). Once
+// the results are exhausted the last one is repeated.
+func (a *Addon) WithReadyResults(results ...ReadyResult) *Addon {
+	a.readyResults = append(a.readyResults, results...)
+	return a
+}
+
+// WithDiagnostics configures the files returned by DumpDiagnostics().
+func (a *Addon) WithDiagnostics(diagnostics map[string][]byte) *Addon {
+	a.diagnostics = diagnostics
+	return a
+}
+
+// DeployCalls reports how many times Deploy() was called.
+func (a *Addon) DeployCalls() int {
+	a.l.Lock()
+	defer a.l.Unlock()
+	return a.deployCalls
+}
+
+// DeleteCalls reports how many times Delete() was called.
+func (a *Addon) DeleteCalls() int {
+	a.l.Lock()
+	defer a.l.Unlock()
+	return a.deleteCalls
+}
+
+// UpgradeCalls reports how many times Upgrade() was called.
+func (a *Addon) UpgradeCalls() int {
+	a.l.Lock()
+	defer a.l.Unlock()
+	return a.upgradeCalls
+}
+
+// ReadyCalls reports how many times Ready() was called.
+func (a *Addon) ReadyCalls() int {
+	a.l.Lock()
+	defer a.l.Unlock()
+	return a.readyCalls
+}
+
+// -----------------------------------------------------------------------------
+// Fake Addon - Addon Implementation
+// -----------------------------------------------------------------------------
+
+func (a *Addon) Name() clusters.AddonName {
+	return a.name
+}
+
+func (a *Addon) Dependencies(_ context.Context, _ clusters.Cluster) []clusters.AddonName {
+	return a.dependencies
+}
+
+func (a *Addon) Deploy(_ context.Context, _ clusters.Cluster) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.deployCalls++
+	return a.deployErr
+}
+
+func (a *Addon) Delete(_ context.Context, _ clusters.Cluster) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.deleteCalls++
+	return a.deleteErr
+}
+
+func (a *Addon) Upgrade(_ context.Context, _ clusters.Cluster, _ clusters.Addon) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.upgradeCalls++
+	return a.upgradeErr
+}
+
+func (a *Addon) DumpDiagnostics(_ context.Context, _ clusters.Cluster) (map[string][]byte, error) {
+	return a.diagnostics, nil
+}
+
+func (a *Addon) Ready(_ context.Context, _ clusters.Cluster) ([]runtime.Object, bool, error) {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	call := a.readyCalls
+	a.readyCalls++
+	if len(a.readyResults) == 0 {
+		return nil, true, nil
+	}
+	result := a.readyResults[min(call, len(a.readyResults)-1)]
+	return result.WaitingForObjects, result.Ready, result.Err
+}
@@ -0,0 +1,121 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// Builder generates in-memory fake clusters.Cluster objects given provided
+// configuration options.
+type Builder struct {
+	Name string
+
+	clusterVersion semver.Version
+	ipFamily       clusters.IPFamily
+	objects        []runtime.Object
+}
+
+// NewBuilder provides a new *Builder object.
+func NewBuilder() *Builder {
+	return &Builder{
+		Name:           uuid.NewString(),
+		clusterVersion: defaultClusterVersion,
+		ipFamily:       clusters.IPv4,
+	}
+}
+
+// WithName indicates a custom name to use for the cluster.
+func (b *Builder) WithName(name string) *Builder {
+	b.Name = name
+	return b
+}
+
+// WithClusterVersion configures the Kubernetes version the fake API server reports.
+func (b *Builder) WithClusterVersion(version semver.Version) *Builder {
+	b.clusterVersion = version
+	return b
+}
+
+// WithIPFamily configures the IP family the cluster reports.
+func (b *Builder) WithIPFamily(family clusters.IPFamily) *Builder {
+	b.ipFamily = family
+	return b
+}
+
+// WithObjects adds objects which will exist in the cluster when it's built.
+// Only types registered with client-go's scheme are supported.
+func (b *Builder) WithObjects(objects ...runtime.Object) *Builder {
+	b.objects = append(b.objects, objects...)
+	return b
+}
+
+// Build creates the in-memory fake cluster. It never fails unless the
+// provided objects can't be added to the object store.
+func (b *Builder) Build(_ context.Context) (clusters.Cluster, error) {
+	return b.build()
+}
+
+// MustBuild is like Build but provides the concrete *Cluster and panics on
+// failure, which is convenient for unit tests.
+func (b *Builder) MustBuild() *Cluster {
+	cluster, err := b.build()
+	if err != nil {
+		panic(err)
+	}
+	return cluster
+}
+
+// New provides a new fake *Cluster containing the given objects.
+func New(objects ...runtime.Object) *Cluster {
+	return NewBuilder().WithObjects(objects...).MustBuild()
+}
+
+// -----------------------------------------------------------------------------
+// Private
+// -----------------------------------------------------------------------------
+
+// defaultClusterVersion is the Kubernetes version reported by fake clusters
+// unless the builder is configured otherwise.
+var defaultClusterVersion = semver.MustParse("1.35.0")
+
+func (b *Builder) build() (*Cluster, error) {
+	clientset := k8sfake.NewClientset()
+	for _, obj := range b.objects {
+		if err := clientset.Tracker().Add(obj); err != nil {
+			return nil, fmt.Errorf("failed to add object %T to fake cluster %s: %w", obj, b.Name, err)
+		}
+	}
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{
+		Major:      fmt.Sprint(b.clusterVersion.Major),
+		Minor:      fmt.Sprint(b.clusterVersion.Minor),
+		GitVersion: "v" + b.clusterVersion.String(),
+	}
+
+	ctrlClient := crfake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjectTracker(clientset.Tracker()).
+		Build()
+
+	return &Cluster{
+		name:       b.Name,
+		clientset:  clientset,
+		ctrlClient: ctrlClient,
+		cfg:        &rest.Config{Host: "https://" + b.Name + ".fake.invalid"},
+		addons:     make(clusters.Addons),
+		l:          &sync.RWMutex{},
+		ipFamily:   b.ipFamily,
+	}, nil
+}
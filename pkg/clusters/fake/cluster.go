@@ -0,0 +1,222 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Fake Cluster
+// -----------------------------------------------------------------------------
+
+const (
+	// FakeClusterType indicates that the Kubernetes cluster is an in-memory fake
+	// which is only suitable for unit tests.
+	FakeClusterType clusters.Type = "fake"
+)
+
+// Cluster is a clusters.Cluster implementation backed by client-go's fake
+// clientset and controller-runtime's fake client, which share a single object
+// store. No API server or nodes exist: addon deployments are recorded and
+// forwarded to the addon, so only addons which use Client() will work.
+type Cluster struct {
+	name       string
+	clientset  *k8sfake.Clientset
+	ctrlClient client.WithWatch
+	cfg        *rest.Config
+	addons     clusters.Addons
+	l          *sync.RWMutex
+	ipFamily   clusters.IPFamily
+
+	deployAddonCalls []clusters.AddonName
+	deleteAddonCalls []clusters.AddonName
+	cleanedUp        bool
+}
+
+// -----------------------------------------------------------------------------
+// Fake Cluster - Cluster Implementation
+// -----------------------------------------------------------------------------
+
+func (c *Cluster) Name() string {
+	return c.name
+}
+
+func (c *Cluster) Type() clusters.Type {
+	return FakeClusterType
+}
+
+func (c *Cluster) Version() (semver.Version, error) {
+	versionInfo, err := c.Client().Discovery().ServerVersion()
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return semver.Parse(strings.TrimPrefix(versionInfo.String(), "v"))
+}
+
+// Cleanup marks the cluster as cleaned up, see CleanedUp().
+func (c *Cluster) Cleanup(_ context.Context) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.cleanedUp = true
+	return nil
+}
+
+func (c *Cluster) Client() kubernetes.Interface {
+	return c.clientset
+}
+
+// Config provides a *rest.Config which points at no real API server, any
+// client built from it will fail to connect.
+func (c *Cluster) Config() *rest.Config {
+	return c.cfg
+}
+
+func (c *Cluster) GetAddon(name clusters.AddonName) (clusters.Addon, error) {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	for addonName, addon := range c.addons {
+		if addonName == name {
+			return addon, nil
+		}
+	}
+
+	return nil, fmt.Errorf("addon %s not found", name)
+}
+
+func (c *Cluster) ListAddons() []clusters.Addon {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	addonList := make([]clusters.Addon, 0, len(c.addons))
+	for _, v := range c.addons {
+		addonList = append(addonList, v)
+	}
+
+	return addonList
+}
+
+func (c *Cluster) DeployAddon(ctx context.Context, addon clusters.Addon) error {
+	c.l.Lock()
+	c.deployAddonCalls = append(c.deployAddonCalls, addon.Name())
+	if _, ok := c.addons[addon.Name()]; ok {
+		c.l.Unlock()
+		return fmt.Errorf("addon component %s is already loaded into cluster %s", addon.Name(), c.Name())
+	}
+	c.addons[addon.Name()] = addon
+	c.l.Unlock()
+
+	if err := addon.Deploy(ctx, c); err != nil {
+		return err
+	}
+
+	return clusters.SaveAddonState(ctx, c, addon)
+}
+
+func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.deleteAddonCalls = append(c.deleteAddonCalls, addon.Name())
+	if _, ok := c.addons[addon.Name()]; !ok {
+		return nil
+	}
+
+	if err := addon.Delete(ctx, c); err != nil {
+		return err
+	}
+
+	if err := clusters.DeleteAddonState(ctx, c, addon.Name()); err != nil {
+		return err
+	}
+
+	delete(c.addons, addon.Name())
+
+	return nil
+}
+
+// DumpDiagnostics collects only the diagnostics of the cluster's addons, as
+// there is no kubectl access to a fake cluster.
+func (c *Cluster) DumpDiagnostics(ctx context.Context, meta string) (string, error) {
+	outDir, err := os.MkdirTemp(os.TempDir(), clusters.DiagnosticOutDirectoryPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	for _, addon := range c.ListAddons() {
+		diagnostics, err := addon.DumpDiagnostics(ctx, c)
+		if err != nil {
+			return outDir, err
+		}
+		addonOut := filepath.Join(outDir, "addons", string(addon.Name()))
+		if err := os.MkdirAll(addonOut, 0o750); err != nil { //nolint:mnd
+			return outDir, err
+		}
+		for filename, content := range diagnostics {
+			if err := os.WriteFile(filepath.Join(addonOut, filename), content, 0o600); err != nil { //nolint:mnd
+				return outDir, err
+			}
+		}
+	}
+
+	return outDir, os.WriteFile(filepath.Join(outDir, "meta.txt"), []byte(meta), 0o600) //nolint:mnd
+}
+
+func (c *Cluster) IPFamily() clusters.IPFamily {
+	return c.ipFamily
+}
+
+// -----------------------------------------------------------------------------
+// Fake Cluster - Test Helpers
+// -----------------------------------------------------------------------------
+
+// Clientset provides the underlying fake clientset, which can be used to add
+// reactors and inspect the actions performed against the cluster.
+func (c *Cluster) Clientset() *k8sfake.Clientset {
+	return c.clientset
+}
+
+// ControllerRuntimeClient provides a controller-runtime client which shares its
+// object store with Client().
+func (c *Cluster) ControllerRuntimeClient() client.WithWatch {
+	return c.ctrlClient
+}
+
+// DeployAddonCalls lists the names of the addons passed to DeployAddon() in
+// the order the calls were made, including calls which failed.
+func (c *Cluster) DeployAddonCalls() []clusters.AddonName {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return append([]clusters.AddonName(nil), c.deployAddonCalls...)
+}
+
+// DeleteAddonCalls lists the names of the addons passed to DeleteAddon() in
+// the order the calls were made, including calls which failed.
+func (c *Cluster) DeleteAddonCalls() []clusters.AddonName {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return append([]clusters.AddonName(nil), c.deleteAddonCalls...)
+}
+
+// CleanedUp indicates whether Cleanup() has been called.
+func (c *Cluster) CleanedUp() bool {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.cleanedUp
+}
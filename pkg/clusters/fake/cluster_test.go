@@ -0,0 +1,87 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestClusterSharesObjectStore(t *testing.T) {
+	ctx := context.Background()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "preloaded"}}
+	cluster := fake.New(ns)
+
+	_, err := cluster.Client().CoreV1().Namespaces().Get(ctx, "preloaded", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "preloaded", Name: "test"}}
+	_, err = cluster.Client().CoreV1().ConfigMaps("preloaded").Create(ctx, cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	got := &corev1.ConfigMap{}
+	require.NoError(t, cluster.ControllerRuntimeClient().Get(ctx, types.NamespacedName{Namespace: "preloaded", Name: "test"}, got))
+
+	version, err := cluster.Version()
+	require.NoError(t, err)
+	assert.Equal(t, "1.35.0", version.String())
+}
+
+func TestClusterRecordsAddonCalls(t *testing.T) {
+	ctx := context.Background()
+	cluster := fake.New()
+	addon := fake.NewAddon("test")
+
+	require.NoError(t, cluster.DeployAddon(ctx, addon))
+	require.Error(t, cluster.DeployAddon(ctx, addon), "an addon can only be deployed once")
+	require.NoError(t, cluster.DeleteAddon(ctx, addon))
+
+	assert.Equal(t, []clusters.AddonName{"test", "test"}, cluster.DeployAddonCalls())
+	assert.Equal(t, []clusters.AddonName{"test"}, cluster.DeleteAddonCalls())
+	assert.Equal(t, 1, addon.DeployCalls())
+	assert.Equal(t, 1, addon.DeleteCalls())
+	assert.Empty(t, cluster.ListAddons())
+}
+
+func TestEnvironmentWithFakeCluster(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cluster := fake.New()
+	addon := fake.NewAddon("test").WithReadyResults(
+		fake.ReadyResult{WaitingForObjects: []runtime.Object{&corev1.Pod{}}},
+		fake.ReadyResult{Ready: true},
+	)
+
+	env, err := environments.NewBuilder().WithExistingCluster(cluster).WithAddons(addon).Build(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []clusters.AddonName{"test"}, cluster.DeployAddonCalls())
+
+	require.NoError(t, <-env.WaitForReady(ctx))
+	assert.Equal(t, 2, addon.ReadyCalls())
+
+	require.NoError(t, env.Cleanup(ctx))
+	assert.True(t, cluster.CleanedUp())
+}
+
+func TestEnvironmentWithFailingAddon(t *testing.T) {
+	ctx := context.Background()
+	deployErr := errors.New("deployment failed")
+
+	_, err := environments.NewBuilder().
+		WithExistingCluster(fake.New()).
+		WithAddons(fake.NewAddon("test").WithDeployError(deployErr)).
+		Build(ctx)
+	require.ErrorIs(t, err, deployErr)
+}
@@ -0,0 +1,259 @@
+package clusters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// -----------------------------------------------------------------------------
+// Public Types - Readiness Reports
+// -----------------------------------------------------------------------------
+
+// ObjectReadiness explains why an object which is blocking readiness is not
+// ready yet.
+type ObjectReadiness struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Condition is the failing status condition of the object, if any (e.g.
+	// "Available=False: MinimumReplicasUnavailable").
+	Condition string
+
+	// ContainerReasons are the reasons the containers of the object's Pods
+	// are waiting (e.g. "proxy: ImagePullBackOff").
+	ContainerReasons []string
+
+	// WarningEvents are the most recent Warning events of the object and its
+	// Pods (e.g. "BackOff: Back-off pulling image").
+	WarningEvents []string
+}
+
+// String provides a single line summary, e.g. "Deployment kong/proxy: ImagePullBackOff".
+func (o ObjectReadiness) String() string {
+	name := o.Name
+	if o.Namespace != "" {
+		name = o.Namespace + "/" + o.Name
+	}
+
+	reasons := slices.Concat(o.ContainerReasons, []string{o.Condition}, o.WarningEvents)
+	reasons = slices.DeleteFunc(reasons, func(reason string) bool { return reason == "" })
+	if len(reasons) == 0 {
+		reasons = []string{"not ready"}
+	}
+
+	return fmt.Sprintf("%s %s: %s", o.Kind, name, strings.Join(reasons, "; "))
+}
+
+// ReadinessReport lists the objects which are blocking readiness along with
+// the reasons they're not ready.
+type ReadinessReport []ObjectReadiness
+
+// String provides a summary of the report with one line per object.
+func (r ReadinessReport) String() string {
+	lines := make([]string, 0, len(r))
+	for _, object := range r {
+		lines = append(lines, object.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// -----------------------------------------------------------------------------
+// Public Functions - Readiness Reports
+// -----------------------------------------------------------------------------
+
+// NewReadinessReport explains why the given objects (as returned by the Ready
+// methods of addons and environments) are not ready. Pods are looked up for
+// Deployments, DaemonSets and StatefulSets to report their container reasons.
+func NewReadinessReport(ctx context.Context, cluster Cluster, objects []runtime.Object) (ReadinessReport, error) {
+	report := make(ReadinessReport, 0, len(objects))
+	for _, obj := range objects {
+		object, err := newObjectReadiness(ctx, cluster, obj)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, object)
+	}
+	return report, nil
+}
+
+// AddonReadinessReport checks the readiness of the addon, providing a report
+// of the objects it's waiting for.
+func AddonReadinessReport(ctx context.Context, cluster Cluster, addon Addon) (ReadinessReport, bool, error) {
+	waitForObjects, ready, err := addon.Ready(ctx, cluster)
+	if err != nil {
+		return nil, false, err
+	}
+	report, err := NewReadinessReport(ctx, cluster, waitForObjects)
+	return report, ready, err
+}
+
+// -----------------------------------------------------------------------------
+// Private Functions - Readiness Reports
+// -----------------------------------------------------------------------------
+
+// maxWarningEvents is the maximum number of Warning events reported per object.
+const maxWarningEvents = 3
+
+func newObjectReadiness(ctx context.Context, cluster Cluster, obj runtime.Object) (ObjectReadiness, error) {
+	var object ObjectReadiness
+	if accessor, err := meta.Accessor(obj); err == nil {
+		object.Namespace, object.Name = accessor.GetNamespace(), accessor.GetName()
+	}
+	object.Kind = obj.GetObjectKind().GroupVersionKind().Kind
+	if object.Kind == "" {
+		if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+			object.Kind = gvks[0].Kind
+		}
+	}
+
+	var selector *metav1.LabelSelector
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		selector = o.Spec.Selector
+		for _, condition := range o.Status.Conditions {
+			// ReplicaFailure is the only Deployment condition which is set when failing
+			failing := condition.Status != corev1.ConditionTrue
+			if condition.Type == appsv1.DeploymentReplicaFailure {
+				failing = condition.Status == corev1.ConditionTrue
+			}
+			if failing {
+				object.Condition = fmt.Sprintf("%s=%s: %s", condition.Type, condition.Status, condition.Reason)
+				break
+			}
+		}
+		if object.Condition == "" && o.Spec.Replicas != nil {
+			object.Condition = fmt.Sprintf("%d/%d replicas available", o.Status.AvailableReplicas, *o.Spec.Replicas)
+		}
+	case *appsv1.DaemonSet:
+		selector = o.Spec.Selector
+		object.Condition = fmt.Sprintf("%d/%d pods available", o.Status.NumberAvailable, o.Status.DesiredNumberScheduled)
+	case *appsv1.StatefulSet:
+		selector = o.Spec.Selector
+		if o.Spec.Replicas != nil {
+			object.Condition = fmt.Sprintf("%d/%d replicas ready", o.Status.ReadyReplicas, *o.Spec.Replicas)
+		}
+	case *corev1.Pod:
+		object.Condition = podCondition(o)
+		object.ContainerReasons = containerReasons(o)
+	case *corev1.Service:
+		if o.Spec.Type == corev1.ServiceTypeLoadBalancer && len(o.Status.LoadBalancer.Ingress) == 0 {
+			object.Condition = "waiting for a load balancer address"
+		}
+	case *corev1.Namespace:
+		if o.UID == "" {
+			object.Condition = "not found"
+		}
+	}
+
+	eventObjects := []string{object.Name}
+	if selector != nil && object.Namespace != "" {
+		pods, err := selectPods(ctx, cluster, object.Namespace, selector)
+		if err != nil {
+			return object, fmt.Errorf("failed to list pods of %s %s/%s: %w", object.Kind, object.Namespace, object.Name, err)
+		}
+		for i := range pods {
+			object.ContainerReasons = append(object.ContainerReasons, containerReasons(&pods[i])...)
+			eventObjects = append(eventObjects, pods[i].Name)
+		}
+		slices.Sort(object.ContainerReasons)
+		object.ContainerReasons = slices.Compact(object.ContainerReasons)
+	}
+
+	if object.Namespace != "" {
+		events, err := warningEvents(ctx, cluster, object.Namespace, eventObjects)
+		if err != nil {
+			return object, err
+		}
+		object.WarningEvents = events
+	}
+
+	return object, nil
+}
+
+// selectPods lists the Pods in the namespace which match the selector.
+func selectPods(ctx context.Context, cluster Cluster, namespace string, selector *metav1.LabelSelector) ([]corev1.Pod, error) {
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := cluster.Client().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// podCondition provides the first failing condition of the Pod.
+func podCondition(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			if condition.Reason != "" {
+				return fmt.Sprintf("%s=%s: %s", condition.Type, condition.Status, condition.Reason)
+			}
+			return fmt.Sprintf("%s=%s", condition.Type, condition.Status)
+		}
+	}
+	return ""
+}
+
+// containerReasons provides the reasons the containers of a Pod are waiting.
+func containerReasons(pod *corev1.Pod) []string {
+	var reasons []string
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", status.Name, status.State.Waiting.Reason))
+		}
+	}
+	return reasons
+}
+
+// warningEvents provides the most recent Warning events involving the named
+// objects in the namespace.
+func warningEvents(ctx context.Context, cluster Cluster, namespace string, names []string) ([]string, error) {
+	events, err := cluster.Client().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+	}
+
+	warnings := slices.DeleteFunc(events.Items, func(event corev1.Event) bool {
+		return event.Type != corev1.EventTypeWarning || !slices.Contains(names, event.InvolvedObject.Name)
+	})
+	slices.SortFunc(warnings, func(a, b corev1.Event) int {
+		return eventTime(a).Compare(eventTime(b))
+	})
+	if len(warnings) > maxWarningEvents {
+		warnings = warnings[len(warnings)-maxWarningEvents:]
+	}
+
+	messages := make([]string, 0, len(warnings))
+	for _, event := range warnings {
+		messages = append(messages, fmt.Sprintf("%s: %s", event.Reason, strings.TrimSpace(event.Message)))
+	}
+	return messages, nil
+}
+
+// eventTime provides the last time an event was observed.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,84 @@
+package clusters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+func TestReadinessReport(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "proxy"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "proxy"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"},
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, Reason: "MinimumReplicasUnavailable"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "proxy-abc", Labels: map[string]string{"app": "proxy"}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "proxy", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				{Name: "sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	unrelatedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "other", Labels: map[string]string{"app": "other"}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "other", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "kong", Name: "proxy-abc.1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "kong", Name: "proxy-abc"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Failed",
+		Message:        "Failed to pull image \"kong:nope\"",
+		LastTimestamp:  metav1.NewTime(time.Now()),
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "proxy"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+	cluster := fake.New(deployment, pod, unrelatedPod, event, service)
+
+	report, err := clusters.NewReadinessReport(ctx, cluster, []runtime.Object{deployment, service})
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+
+	assert.Equal(t, clusters.ObjectReadiness{
+		Kind:             "Deployment",
+		Namespace:        "kong",
+		Name:             "proxy",
+		Condition:        "Available=False: MinimumReplicasUnavailable",
+		ContainerReasons: []string{"proxy: ImagePullBackOff"},
+		WarningEvents:    []string{"Failed: Failed to pull image \"kong:nope\""},
+	}, report[0])
+	assert.Equal(t, "Service kong/proxy: waiting for a load balancer address", report[1].String())
+	assert.Equal(t,
+		"Deployment kong/proxy: proxy: ImagePullBackOff; Available=False: MinimumReplicasUnavailable; Failed: Failed to pull image \"kong:nope\"\n"+
+			"Service kong/proxy: waiting for a load balancer address",
+		report.String(),
+	)
+}
@@ -0,0 +1,220 @@
+package clusters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// -----------------------------------------------------------------------------
+// Test Namespaces - Public
+// -----------------------------------------------------------------------------
+
+const (
+	// TestNamespaceCreatorID is the creator ID (see GenerateNamespace) of the
+	// namespaces created by NewTestNamespace.
+	TestNamespaceCreatorID = "ktf-test-namespace"
+
+	// EnvTestArtifactsDir is the environment variable which can be set to a
+	// directory where NewTestNamespace dumps the diagnostics of failed tests.
+	EnvTestArtifactsDir = "KTF_TEST_ARTIFACTS_DIR"
+)
+
+// NewTestNamespace generates a namespace for the test. Once the test is done
+// the namespace is deleted, and if the test failed the namespace's objects,
+// events and pod logs are dumped beforehand. The diagnostics are dumped into a
+// subdirectory of EnvTestArtifactsDir named after the test, or into a new
+// temporary directory if it's not set (not t.TempDir(), which is removed along
+// with the test), whose path is logged.
+func NewTestNamespace(t testing.TB, cluster Cluster) *corev1.Namespace {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testNamespaceTimeout)
+	defer cancel()
+
+	namespace, err := GenerateNamespace(ctx, cluster, TestNamespaceCreatorID)
+	if err != nil {
+		t.Fatalf("failed to generate a test namespace: %s", err)
+	}
+
+	cleaner := NewCleaner(cluster, scheme.Scheme)
+	cleaner.AddNamespace(namespace)
+
+	t.Cleanup(func() {
+		// the test context is already done when cleanups run
+		ctx, cancel := context.WithTimeout(context.Background(), testNamespaceTimeout)
+		defer cancel()
+
+		if t.Failed() {
+			outDir, err := testArtifactsDir(t)
+			if err == nil {
+				err = DumpNamespaceDiagnostics(ctx, cluster, namespace.Name, outDir)
+			}
+			if err != nil {
+				t.Logf("failed to dump the diagnostics of namespace %s: %s", namespace.Name, err)
+			} else {
+				t.Logf("dumped the diagnostics of namespace %s to %s", namespace.Name, outDir)
+			}
+		}
+
+		if err := cleaner.Cleanup(ctx); err != nil {
+			t.Errorf("failed to delete test namespace %s: %s", namespace.Name, err)
+		}
+	})
+
+	return namespace
+}
+
+// DumpNamespaceDiagnostics dumps the objects (except Secrets), events and pod
+// logs of a single namespace into outDir.
+func DumpNamespaceDiagnostics(ctx context.Context, cluster Cluster, namespace string, outDir string) error {
+	var errs []error
+	if err := dumpNamespaceEvents(ctx, cluster, namespace, outDir); err != nil {
+		errs = append(errs, err)
+	}
+	if err := dumpNamespacePodLogs(ctx, cluster, namespace, filepath.Join(outDir, "logs")); err != nil {
+		errs = append(errs, err)
+	}
+	if err := dumpNamespaceObjects(ctx, cluster, namespace, filepath.Join(outDir, "objects")); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// -----------------------------------------------------------------------------
+// Test Namespaces - Private
+// -----------------------------------------------------------------------------
+
+// testNamespaceTimeout is the maximum amount of time to create, dump or delete
+// a test namespace.
+const testNamespaceTimeout = time.Minute * 2
+
+// unsafePathCharacters matches the characters of test names which aren't kept
+// in artifact directory names.
+var unsafePathCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// testArtifactsDir provides the directory to dump the diagnostics of a test to.
+func testArtifactsDir(t testing.TB) (string, error) {
+	root := os.Getenv(EnvTestArtifactsDir)
+	if root == "" {
+		return os.MkdirTemp(os.TempDir(), DiagnosticOutDirectoryPrefix)
+	}
+	dir := filepath.Join(root, unsafePathCharacters.ReplaceAllString(t.Name(), "_"))
+	return dir, os.MkdirAll(dir, 0o750) //nolint:mnd
+}
+
+func dumpNamespaceEvents(ctx context.Context, cluster Cluster, namespace string, outDir string) error {
+	events, err := cluster.Client().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+	slices.SortFunc(events.Items, func(a, b corev1.Event) int {
+		return eventTime(a).Compare(eventTime(b))
+	})
+	return writeYAML(filepath.Join(outDir, "events.yaml"), events)
+}
+
+func dumpNamespacePodLogs(ctx context.Context, cluster Cluster, namespace string, outDir string) error {
+	pods, err := cluster.Client().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var errs []error
+	for _, pod := range pods.Items {
+		podOut := filepath.Join(outDir, pod.Name)
+		if err := os.MkdirAll(podOut, 0o750); err != nil { //nolint:mnd
+			return err
+		}
+		for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+			logs, err := cluster.Client().CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container.Name}).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get logs of container %s of pod %s: %w", container.Name, pod.Name, err))
+				continue
+			}
+			if err := os.WriteFile(filepath.Join(podOut, container.Name+".log"), logs, 0o600); err != nil { //nolint:mnd
+				return err
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func dumpNamespaceObjects(ctx context.Context, cluster Cluster, namespace string, outDir string) error {
+	// discovery can partially fail (e.g. for unavailable aggregated APIs), so
+	// whatever was discovered is dumped anyways.
+	var errs []error
+	resourceLists, err := cluster.Client().Discovery().ServerPreferredNamespacedResources()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to discover namespaced resources: %w", err))
+	}
+
+	var resources []schema.GroupVersionResource
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			// events are dumped separately and secrets are left out of artifacts
+			if !slices.Contains(resource.Verbs, "list") || resource.Name == "events" || resource.Name == "secrets" {
+				continue
+			}
+			resources = append(resources, gv.WithResource(resource.Name))
+		}
+	}
+	if len(resources) == 0 {
+		return errors.Join(errs...)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cluster.Config())
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if err := os.MkdirAll(outDir, 0o750); err != nil { //nolint:mnd
+		return errors.Join(append(errs, err)...)
+	}
+	for _, resource := range resources {
+		objects, err := dynamicClient.Resource(resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list %s: %w", resource, err))
+			continue
+		}
+		if len(objects.Items) == 0 {
+			continue
+		}
+		filename := resource.Resource + ".yaml"
+		if resource.Group != "" {
+			filename = resource.Resource + "." + resource.Group + ".yaml"
+		}
+		if err := writeYAML(filepath.Join(outDir, filename), objects); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeYAML(filename string, obj any) error {
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(filename), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o750); err != nil { //nolint:mnd
+		return err
+	}
+	return os.WriteFile(filename, content, 0o600) //nolint:mnd
+}
@@ -0,0 +1,84 @@
+package clusters_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+)
+
+// scriptedT lets tests decide whether the test using a namespace failed and
+// when its cleanups run.
+type scriptedT struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+}
+
+func (s *scriptedT) Failed() bool {
+	return s.failed
+}
+
+func (s *scriptedT) Cleanup(cleanup func()) {
+	s.cleanups = append(s.cleanups, cleanup)
+}
+
+func (s *scriptedT) runCleanups() {
+	for i := len(s.cleanups) - 1; i >= 0; i-- {
+		s.cleanups[i]()
+	}
+}
+
+func TestNewTestNamespace(t *testing.T) {
+	for _, failed := range []bool{false, true} {
+		t.Run(map[bool]string{false: "passed", true: "failed"}[failed], func(t *testing.T) {
+			ctx := context.Background()
+			artifacts := t.TempDir()
+			t.Setenv(clusters.EnvTestArtifactsDir, artifacts)
+
+			cluster := fake.New()
+			st := &scriptedT{TB: t, failed: failed}
+			namespace := clusters.NewTestNamespace(st, cluster)
+			assert.Equal(t, clusters.TestNamespaceCreatorID, namespace.Labels[clusters.TestResourceLabel])
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace.Name, Name: "httpbin"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "httpbin"}}},
+			}
+			_, err := cluster.Client().CoreV1().Pods(namespace.Name).Create(ctx, pod, metav1.CreateOptions{})
+			require.NoError(t, err)
+			event := &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace.Name, Name: "httpbin.1"},
+				Type:       corev1.EventTypeWarning,
+				Reason:     "BackOff",
+			}
+			_, err = cluster.Client().CoreV1().Events(namespace.Name).Create(ctx, event, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			st.runCleanups()
+
+			_, err = cluster.Client().CoreV1().Namespaces().Get(ctx, namespace.Name, metav1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err), "the namespace is deleted")
+
+			outDir := filepath.Join(artifacts, strings.ReplaceAll(t.Name(), "/", "_"))
+			if !failed {
+				assert.NoDirExists(t, outDir, "diagnostics are only dumped for failed tests")
+				return
+			}
+			events, err := os.ReadFile(filepath.Join(outDir, "events.yaml"))
+			require.NoError(t, err)
+			assert.Contains(t, string(events), "reason: BackOff")
+			assert.FileExists(t, filepath.Join(outDir, "logs", "httpbin", "httpbin.log"))
+		})
+	}
+}
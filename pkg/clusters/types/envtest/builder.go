@@ -0,0 +1,131 @@
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	crenvtest "sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/kong/kubernetes-testing-framework/internal/utils"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// Builder generates clusters.Cluster objects backed by a local kube-apiserver
+// and etcd started by controller-runtime's envtest given provided configuration
+// options.
+type Builder struct {
+	Name string
+
+	clusterVersion        *semver.Version
+	binaryAssetsDirectory string
+	crdPaths              []string
+	crds                  []*apiextensionsv1.CustomResourceDefinition
+	errorIfCRDPathMissing bool
+}
+
+// NewBuilder provides a new *Builder object.
+func NewBuilder() *Builder {
+	return &Builder{
+		Name:                  uuid.NewString(),
+		errorIfCRDPathMissing: true,
+	}
+}
+
+// WithName indicates a custom name to use for the cluster.
+func (b *Builder) WithName(name string) *Builder {
+	b.Name = name
+	return b
+}
+
+// WithClusterVersion configures the Kubernetes version of the control plane
+// binaries, which will be downloaded if they are not already present.
+func (b *Builder) WithClusterVersion(version semver.Version) *Builder {
+	b.clusterVersion = &version
+	return b
+}
+
+// WithBinaryAssetsDirectory configures the directory where the kube-apiserver,
+// etcd and kubectl binaries can be found. If unset the KUBEBUILDER_ASSETS
+// environment variable is used.
+func (b *Builder) WithBinaryAssetsDirectory(dir string) *Builder {
+	b.binaryAssetsDirectory = dir
+	return b
+}
+
+// WithCRDPaths adds files or directories containing CRD manifests which will be
+// installed when the cluster starts.
+func (b *Builder) WithCRDPaths(paths ...string) *Builder {
+	b.crdPaths = append(b.crdPaths, paths...)
+	return b
+}
+
+// WithCRDs adds CRD objects which will be installed when the cluster starts.
+func (b *Builder) WithCRDs(crds ...*apiextensionsv1.CustomResourceDefinition) *Builder {
+	b.crds = append(b.crds, crds...)
+	return b
+}
+
+// WithIgnoreMissingCRDPaths configures the builder to tolerate CRD paths which
+// don't exist rather than failing to build the cluster.
+func (b *Builder) WithIgnoreMissingCRDPaths() *Builder {
+	b.errorIfCRDPathMissing = false
+	return b
+}
+
+// Build starts the control plane processes and configures clients for an
+// envtest-based Kubernetes clusters.Cluster.
+func (b *Builder) Build(ctx context.Context) (clusters.Cluster, error) {
+	env := &crenvtest.Environment{
+		BinaryAssetsDirectory: b.binaryAssetsDirectory,
+		CRDDirectoryPaths:     b.crdPaths,
+		CRDs:                  b.crds,
+		ErrorIfCRDPathMissing: b.errorIfCRDPathMissing,
+	}
+	if b.clusterVersion != nil {
+		env.DownloadBinaryAssets = true
+		env.DownloadBinaryAssetsVersion = "v" + b.clusterVersion.String()
+	}
+
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start control plane for cluster %s: %w", b.Name, err)
+	}
+
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		if stopErr := env.Stop(); stopErr != nil {
+			return nil, fmt.Errorf("multiple errors occurred BUILD_ERROR=(%s) CLEANUP_ERROR=(%s)", err, stopErr)
+		}
+		return nil, err
+	}
+
+	cluster := &Cluster{
+		name:     b.Name,
+		env:      env,
+		client:   kc,
+		cfg:      cfg,
+		addons:   make(clusters.Addons),
+		l:        &sync.RWMutex{},
+		ipFamily: clusters.IPv4,
+	}
+
+	// utils.ClusterInitHooks can't be used here as it waits for the admin namespace's
+	// default ServiceAccount, which is never created without a controller-manager.
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: utils.AdminNamespace}}
+	if _, err := kc.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		if cleanupErr := cluster.Cleanup(ctx); cleanupErr != nil {
+			return nil, fmt.Errorf("multiple errors occurred BUILD_ERROR=(%s) CLEANUP_ERROR=(%s)", err, cleanupErr)
+		}
+		return nil, err
+	}
+
+	return cluster, nil
+}
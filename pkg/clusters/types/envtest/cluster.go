@@ -0,0 +1,171 @@
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	crenvtest "sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// EnvTest Cluster
+// -----------------------------------------------------------------------------
+
+const (
+	// EnvTestClusterType indicates that the Kubernetes cluster is a control plane
+	// only cluster (kube-apiserver and etcd) started by controller-runtime's envtest.
+	EnvTestClusterType clusters.Type = "envtest"
+)
+
+// Cluster is a clusters.Cluster implementation backed by a local kube-apiserver
+// and etcd. It provides API semantics only: there are no nodes, so no Pods will
+// ever run and addons which need workloads can't be deployed to it.
+type Cluster struct {
+	name     string
+	env      *crenvtest.Environment
+	client   *kubernetes.Clientset
+	cfg      *rest.Config
+	addons   clusters.Addons
+	l        *sync.RWMutex
+	ipFamily clusters.IPFamily
+}
+
+// New provides a new clusters.Cluster backed by an envtest control plane.
+func New(ctx context.Context) (*Cluster, error) {
+	cluster, err := NewBuilder().Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.(*Cluster), nil
+}
+
+// -----------------------------------------------------------------------------
+// EnvTest Cluster - Cluster Implementation
+// -----------------------------------------------------------------------------
+
+func (c *Cluster) Name() string {
+	return c.name
+}
+
+func (c *Cluster) Type() clusters.Type {
+	return EnvTestClusterType
+}
+
+func (c *Cluster) Version() (semver.Version, error) {
+	versionInfo, err := c.Client().Discovery().ServerVersion()
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return semver.Parse(strings.TrimPrefix(versionInfo.String(), "v"))
+}
+
+// Cleanup stops the kube-apiserver and etcd processes backing the cluster.
+func (c *Cluster) Cleanup(_ context.Context) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	return c.env.Stop()
+}
+
+func (c *Cluster) Client() kubernetes.Interface {
+	return c.client
+}
+
+func (c *Cluster) Config() *rest.Config {
+	return c.cfg
+}
+
+func (c *Cluster) GetAddon(name clusters.AddonName) (clusters.Addon, error) {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	for addonName, addon := range c.addons {
+		if addonName == name {
+			return addon, nil
+		}
+	}
+
+	return nil, fmt.Errorf("addon %s not found", name)
+}
+
+func (c *Cluster) ListAddons() []clusters.Addon {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	addonList := make([]clusters.Addon, 0, len(c.addons))
+	for _, v := range c.addons {
+		addonList = append(addonList, v)
+	}
+
+	return addonList
+}
+
+func (c *Cluster) DeployAddon(ctx context.Context, addon clusters.Addon) error {
+	c.l.Lock()
+	if _, ok := c.addons[addon.Name()]; ok {
+		c.l.Unlock()
+		return fmt.Errorf("addon component %s is already loaded into cluster %s", addon.Name(), c.Name())
+	}
+	c.addons[addon.Name()] = addon
+	c.l.Unlock()
+
+	if err := addon.Deploy(ctx, c); err != nil {
+		return err
+	}
+
+	return clusters.SaveAddonState(ctx, c, addon)
+}
+
+func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if _, ok := c.addons[addon.Name()]; !ok {
+		return nil
+	}
+
+	if err := addon.Delete(ctx, c); err != nil {
+		return err
+	}
+
+	if err := clusters.DeleteAddonState(ctx, c, addon.Name()); err != nil {
+		return err
+	}
+
+	delete(c.addons, addon.Name())
+
+	return nil
+}
+
+// DumpDiagnostics produces diagnostics data for the cluster at a given time.
+// It uses the provided meta string to write to meta.txt file which will allow
+// for diagnostics identification.
+// It returns the path to directory containing all the diagnostic files and an error.
+func (c *Cluster) DumpDiagnostics(ctx context.Context, meta string) (string, error) {
+	// create a tempdir
+	outDir, err := os.MkdirTemp(os.TempDir(), clusters.DiagnosticOutDirectoryPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	// there are no nodes or pods to collect logs from, only API objects.
+	err = clusters.DumpDiagnostics(ctx, c, meta, outDir)
+	return outDir, err
+}
+
+func (c *Cluster) IPFamily() clusters.IPFamily {
+	return c.ipFamily
+}
+
+// SupportsWorkloads reports that this cluster can't run workloads.
+func (c *Cluster) SupportsWorkloads() bool {
+	return false
+}
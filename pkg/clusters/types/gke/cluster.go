@@ -74,6 +74,12 @@ func NewFromExisting(ctx context.Context, name, project, location string, jsonCr
 		return nil, err
 	}
 
+	// rehydrate any addons which were deployed to the cluster by another process.
+	addons, err := clusters.LoadAddons(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Cluster{
 		name:      name,
 		project:   project,
@@ -81,7 +87,7 @@ func NewFromExisting(ctx context.Context, name, project, location string, jsonCr
 		jsonCreds: jsonCreds,
 		client:    client,
 		cfg:       cfg,
-		addons:    make(clusters.Addons),
+		addons:    addons,
 		l:         &sync.RWMutex{},
 	}, nil
 }
@@ -99,7 +105,7 @@ func (c *Cluster) Type() clusters.Type {
 }
 
 func (c *Cluster) Version() (semver.Version, error) {
-	versionInfo, err := c.Client().ServerVersion()
+	versionInfo, err := c.Client().Discovery().ServerVersion()
 	if err != nil {
 		return semver.Version{}, err
 	}
@@ -156,7 +162,7 @@ func waitForTeardown(ctx context.Context, mgrc *container.ClusterManagerClient,
 	}
 }
 
-func (c *Cluster) Client() *kubernetes.Clientset {
+func (c *Cluster) Client() kubernetes.Interface {
 	return c.client
 }
 
@@ -198,7 +204,11 @@ func (c *Cluster) DeployAddon(ctx context.Context, addon clusters.Addon) error {
 	c.addons[addon.Name()] = addon
 	c.l.Unlock()
 
-	return addon.Deploy(ctx, c)
+	if err := addon.Deploy(ctx, c); err != nil {
+		return err
+	}
+
+	return clusters.SaveAddonState(ctx, c, addon)
 }
 
 func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
@@ -213,6 +223,10 @@ func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
 		return err
 	}
 
+	if err := clusters.DeleteAddonState(ctx, c, addon.Name()); err != nil {
+		return err
+	}
+
 	delete(c.addons, addon.Name())
 
 	return nil
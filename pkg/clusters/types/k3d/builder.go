@@ -0,0 +1,117 @@
+package k3d
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/uuid"
+
+	"github.com/kong/kubernetes-testing-framework/internal/utils"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// Builder generates clusters.Cluster objects backed by k3d given
+// provided configuration options.
+type Builder struct {
+	Name string
+
+	addons         clusters.Addons
+	clusterVersion *semver.Version
+	configPath     *string
+	agents         int
+}
+
+// NewBuilder provides a new *Builder object.
+func NewBuilder() *Builder {
+	return &Builder{
+		// k3d limits cluster names to 32 characters, so a full UUID can't be used.
+		Name:   fmt.Sprintf("ktf-%s", uuid.NewString()[:8]),
+		addons: make(clusters.Addons),
+	}
+}
+
+// WithName indicates a custom name to use for the cluster.
+func (b *Builder) WithName(name string) *Builder {
+	b.Name = name
+	return b
+}
+
+// WithClusterVersion configures the Kubernetes cluster version for the Builder
+// to use when building the k3d cluster.
+func (b *Builder) WithClusterVersion(version semver.Version) *Builder {
+	b.clusterVersion = &version
+	return b
+}
+
+// WithConfig sets a filename containing a k3d config
+// See: https://k3d.io/stable/usage/configfile/
+func (b *Builder) WithConfig(filename string) *Builder {
+	b.configPath = &filename
+	return b
+}
+
+// WithAgents configures the number of agent (worker) nodes which will be
+// created alongside the k3s server node.
+func (b *Builder) WithAgents(agents int) *Builder {
+	b.agents = agents
+	return b
+}
+
+// Build creates and configures clients for a k3d-based Kubernetes clusters.Cluster.
+func (b *Builder) Build(ctx context.Context) (clusters.Cluster, error) {
+	deployArgs := []string{
+		"--wait",
+		// the bundled ingress controller and service load balancer would compete
+		// with the addons (e.g. kong and metallb) which are used for testing.
+		"--k3s-arg", "--disable=traefik@server:*",
+		"--k3s-arg", "--disable=servicelb@server:*",
+	}
+	if b.clusterVersion != nil {
+		deployArgs = append(deployArgs, "--image", imageForVersion(*b.clusterVersion))
+	}
+	if b.agents > 0 {
+		deployArgs = append(deployArgs, "--agents", fmt.Sprintf("%d", b.agents))
+	}
+	if b.configPath != nil {
+		deployArgs = append(deployArgs, "--config", *b.configPath)
+	}
+
+	args := append([]string{"cluster", "create", b.Name}, deployArgs...)
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, "k3d", args...)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to create cluster %s: %s: %w", b.Name, stderr.String(), err)
+	}
+
+	cfg, kc, err := clientForCluster(b.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &Cluster{
+		name:       b.Name,
+		client:     kc,
+		cfg:        cfg,
+		addons:     make(clusters.Addons),
+		deployArgs: deployArgs,
+		l:          &sync.RWMutex{},
+		ipFamily:   clusters.IPv4,
+	}
+
+	if err := utils.ClusterInitHooks(ctx, cluster); err != nil {
+		if cleanupErr := cluster.Cleanup(ctx); cleanupErr != nil {
+			return nil, fmt.Errorf("multiple errors occurred BUILD_ERROR=(%s) CLEANUP_ERROR=(%s)", err, cleanupErr)
+		}
+		return nil, err
+	}
+
+	return cluster, nil
+}
@@ -0,0 +1,157 @@
+package k3d
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Public Functions - Existing Cluster
+// -----------------------------------------------------------------------------
+
+// NewFromExisting provides a Cluster object for a given k3d cluster by name.
+func NewFromExisting(name string) (clusters.Cluster, error) {
+	cfg, kc, err := clientForCluster(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// rehydrate any addons which were deployed to the cluster by another process.
+	ctx, cancel := context.WithTimeout(context.Background(), addonStateLoadTimeout)
+	defer cancel()
+	addons, err := clusters.LoadAddons(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cluster{
+		name:     name,
+		client:   kc,
+		cfg:      cfg,
+		l:        &sync.RWMutex{},
+		addons:   addons,
+		ipFamily: clusters.IPv4,
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Private Consts & Vars
+// -----------------------------------------------------------------------------
+
+const (
+	// addonStateLoadTimeout is the maximum amount of time to wait for the state
+	// of addons deployed to an existing cluster to be retrieved.
+	addonStateLoadTimeout = time.Second * 30
+
+	// k3sImageRepository is the container image repository for k3s node images.
+	k3sImageRepository = "rancher/k3s"
+
+	// k3sImageSuffix is the suffix that k3s appends to the upstream Kubernetes
+	// version for its first release of that version.
+	k3sImageSuffix = "-k3s1"
+)
+
+// -----------------------------------------------------------------------------
+// Private Functions - Cluster Management
+// -----------------------------------------------------------------------------
+
+// imageForVersion provides the k3s node image for a given Kubernetes version.
+// Versions reported by k3s servers (e.g. 1.30.2+k3s2) keep their k3s release.
+func imageForVersion(version semver.Version) string {
+	suffix := k3sImageSuffix
+	if len(version.Build) > 0 && strings.HasPrefix(version.Build[0], "k3s") {
+		suffix = "-" + version.Build[0]
+	}
+	return fmt.Sprintf("%s:v%d.%d.%d%s", k3sImageRepository, version.Major, version.Minor, version.Patch, suffix)
+}
+
+// deleteK3dCluster deletes an existing k3d cluster.
+func deleteK3dCluster(ctx context.Context, name string) error {
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, "k3d", "cluster", "delete", name)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", stderr.String(), err)
+	}
+
+	return nil
+}
+
+// clientForCluster provides a *kubernetes.Clientset for a k3d cluster provided the cluster name.
+func clientForCluster(name string) (*rest.Config, *kubernetes.Clientset, error) {
+	kubeconfig := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command("k3d", "kubeconfig", "get", name)
+	cmd.Stdout = kubeconfig
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, nil, fmt.Errorf("command %q failed STDERR=(%s): %w", cmd.String(), stderr.String(), err)
+	}
+
+	clientCfg, err := clientcmd.NewClientConfigFromBytes(kubeconfig.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := clientCfg.ClientConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	return cfg, clientset, err
+}
+
+// exportLogs dumps the logs of every node container of a k3d cluster to the specified directory.
+func exportLogs(ctx context.Context, name string, outDir string) error {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, "docker", "ps", "--all", //nolint:gosec
+		"--filter", fmt.Sprintf("label=k3d.cluster=%s", name),
+		"--format", "{{.Names}}",
+	)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", stderr.String(), err)
+	}
+
+	logsDir := filepath.Join(outDir, "node_logs")
+	if err := os.MkdirAll(logsDir, 0o750); err != nil { //nolint:mnd
+		return err
+	}
+
+	for _, container := range strings.Fields(stdout.String()) {
+		logOut, err := os.Create(filepath.Join(logsDir, container+".log"))
+		if err != nil {
+			return err
+		}
+		// docker logs replays the container's stdout and stderr streams separately,
+		// so both are written to the same file to keep the ordering readable.
+		cmd := exec.CommandContext(ctx, "docker", "logs", container)
+		cmd.Stdout = logOut
+		cmd.Stderr = logOut
+		err = cmd.Run()
+		logOut.Close()
+		if err != nil {
+			return fmt.Errorf("failed to export logs for container %s: %w", container, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package k3d
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageForVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		version  semver.Version
+		expected string
+	}{
+		{
+			name:     "plain version",
+			version:  semver.MustParse("1.29.4"),
+			expected: "rancher/k3s:v1.29.4-k3s1",
+		},
+		{
+			name:     "version with build metadata",
+			version:  semver.MustParse("1.30.2+k3s2"),
+			expected: "rancher/k3s:v1.30.2-k3s2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, imageForVersion(tc.version))
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 
 	"github.com/kong/kubernetes-testing-framework/internal/utils"
 	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
@@ -26,6 +28,19 @@ type Builder struct {
 	configReader   io.Reader
 	calicoCNI      bool
 	ipv6Only       bool
+	dualStack      bool
+
+	controlPlaneNodes int
+	workerNodes       int
+	nodeLabels        map[nodeKey]map[string]string
+	nodeTaints        map[nodeKey][]corev1.Taint
+}
+
+// nodeKey identifies a node of a kind cluster by its role and its index among
+// the nodes with that role.
+type nodeKey struct {
+	role  v1alpha4.NodeRole
+	index int
 }
 
 // NewBuilder provides a new *Builder object.
@@ -81,6 +96,56 @@ func (b *Builder) WithIPv6Only() *Builder {
 	return b
 }
 
+// WithDualStack configures KIND to use both IPv4 and IPv6.
+func (b *Builder) WithDualStack() *Builder {
+	b.dualStack = true
+	return b
+}
+
+// WithControlPlaneNodes configures the number of control plane nodes of the
+// cluster. Kind runs a load balancer in front of the API servers when there is
+// more than one, making the control plane highly available.
+func (b *Builder) WithControlPlaneNodes(n int) *Builder {
+	b.controlPlaneNodes = n
+	return b
+}
+
+// WithWorkerNodes configures the number of worker nodes of the cluster. Once
+// the cluster has workers, the control plane nodes are tainted so that
+// workloads are scheduled on the workers.
+func (b *Builder) WithWorkerNodes(n int) *Builder {
+	b.workerNodes = n
+	return b
+}
+
+// WithNodeLabels adds labels to a node, identified by its role and its (zero
+// based) index among the nodes with that role.
+func (b *Builder) WithNodeLabels(role v1alpha4.NodeRole, index int, labels map[string]string) *Builder {
+	if b.nodeLabels == nil {
+		b.nodeLabels = make(map[nodeKey]map[string]string)
+	}
+	key := nodeKey{role: role, index: index}
+	if b.nodeLabels[key] == nil {
+		b.nodeLabels[key] = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		b.nodeLabels[key][k] = v
+	}
+	return b
+}
+
+// WithNodeTaints registers a node, identified by its role and its (zero based)
+// index among the nodes with that role, with the given taints. Tainting a
+// control plane node replaces the taints it would otherwise be registered with.
+func (b *Builder) WithNodeTaints(role v1alpha4.NodeRole, index int, taints ...corev1.Taint) *Builder {
+	if b.nodeTaints == nil {
+		b.nodeTaints = make(map[nodeKey][]corev1.Taint)
+	}
+	key := nodeKey{role: role, index: index}
+	b.nodeTaints[key] = append(b.nodeTaints[key], taints...)
+	return b
+}
+
 // Build creates and configures clients for a Kind-based Kubernetes clusters.Cluster.
 func (b *Builder) Build(ctx context.Context) (clusters.Cluster, error) {
 	deployArgs := make([]string, 0)
@@ -99,12 +164,28 @@ func (b *Builder) Build(ctx context.Context) (clusters.Cluster, error) {
 		deployArgs = append(deployArgs, "--wait", "1s")
 	}
 
+	if b.ipv6Only && b.dualStack {
+		return nil, fmt.Errorf("IPv6-only and dual-stack networking can't be configured together")
+	}
+
 	if b.ipv6Only {
 		if err := b.useIPv6Only(); err != nil {
 			return nil, fmt.Errorf("failed configuring IPv6-only networking: %w", err)
 		}
 	}
 
+	if b.dualStack {
+		if err := b.useDualStack(); err != nil {
+			return nil, fmt.Errorf("failed configuring dual-stack networking: %w", err)
+		}
+	}
+
+	if b.controlPlaneNodes != 0 || b.workerNodes != 0 || len(b.nodeLabels) != 0 || len(b.nodeTaints) != 0 {
+		if err := b.configureNodes(); err != nil {
+			return nil, fmt.Errorf("failed configuring kind cluster nodes: %w", err)
+		}
+	}
+
 	var stdin io.Reader
 	if b.configPath != nil {
 		deployArgs = append(deployArgs, "--config", *b.configPath)
@@ -130,8 +211,11 @@ func (b *Builder) Build(ctx context.Context) (clusters.Cluster, error) {
 	}
 
 	ipFamily := clusters.IPv4
-	if b.ipv6Only {
+	switch {
+	case b.ipv6Only:
 		ipFamily = clusters.IPv6
+	case b.dualStack:
+		ipFamily = clusters.Dual
 	}
 
 	cluster := &Cluster{
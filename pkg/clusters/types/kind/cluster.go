@@ -60,7 +60,7 @@ func (c *Cluster) Type() clusters.Type {
 }
 
 func (c *Cluster) Version() (semver.Version, error) {
-	versionInfo, err := c.Client().ServerVersion()
+	versionInfo, err := c.Client().Discovery().ServerVersion()
 	if err != nil {
 		return semver.Version{}, err
 	}
@@ -78,7 +78,7 @@ func (c *Cluster) Cleanup(ctx context.Context) error {
 	return nil
 }
 
-func (c *Cluster) Client() *kubernetes.Clientset {
+func (c *Cluster) Client() kubernetes.Interface {
 	return c.client
 }
 
@@ -120,7 +120,11 @@ func (c *Cluster) DeployAddon(ctx context.Context, addon clusters.Addon) error {
 	c.addons[addon.Name()] = addon
 	c.l.Unlock()
 
-	return addon.Deploy(ctx, c)
+	if err := addon.Deploy(ctx, c); err != nil {
+		return err
+	}
+
+	return clusters.SaveAddonState(ctx, c, addon)
 }
 
 func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
@@ -135,6 +139,10 @@ func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
 		return err
 	}
 
+	if err := clusters.DeleteAddonState(ctx, c, addon.Name()); err != nil {
+		return err
+	}
+
 	delete(c.addons, addon.Name())
 
 	return nil
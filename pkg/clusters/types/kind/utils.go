@@ -5,10 +5,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -28,12 +32,21 @@ func NewFromExisting(name string) (clusters.Cluster, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// rehydrate any addons which were deployed to the cluster by another process.
+	ctx, cancel := context.WithTimeout(context.Background(), addonStateLoadTimeout)
+	defer cancel()
+	addons, err := clusters.LoadAddons(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Cluster{
 		name:   name,
 		client: kc,
 		cfg:    cfg,
 		l:      &sync.RWMutex{},
-		addons: make(clusters.Addons),
+		addons: addons,
 	}, nil
 }
 
@@ -42,6 +55,10 @@ func NewFromExisting(name string) (clusters.Cluster, error) {
 // -----------------------------------------------------------------------------
 
 const (
+	// addonStateLoadTimeout is the maximum amount of time to wait for the state
+	// of addons deployed to an existing cluster to be retrieved.
+	addonStateLoadTimeout = time.Second * 30
+
 	defaultCalicoManifests = "https://raw.githubusercontent.com/projectcalico/calico/v3.25.0/manifests/calico.yaml"
 )
 
@@ -100,8 +117,13 @@ func (b *Builder) ensureConfigFile() error {
 		}
 		defer f.Close()
 
-		_, err = f.WriteString(defaultKindConfig)
-		if err != nil {
+		// a config provided through a reader is written to the file so that it can be patched.
+		if b.configReader != nil {
+			if _, err := io.Copy(f, b.configReader); err != nil {
+				return fmt.Errorf("failed writing kind config to %s: %w", f.Name(), err)
+			}
+			b.configReader = nil
+		} else if _, err := f.WriteString(defaultKindConfig); err != nil {
 			return err
 		}
 
@@ -112,7 +134,9 @@ func (b *Builder) ensureConfigFile() error {
 	return nil
 }
 
-func (b *Builder) disableDefaultCNI() error {
+// patchConfig applies the given function to the builder's kind config, creating
+// a config file if one wasn't already provided.
+func (b *Builder) patchConfig(patch func(kindConfig *v1alpha4.Cluster) error) error {
 	if err := b.ensureConfigFile(); err != nil {
 		return err
 	}
@@ -127,7 +151,9 @@ func (b *Builder) disableDefaultCNI() error {
 		return fmt.Errorf("failed unmarshalling kind config: %w", err)
 	}
 
-	kindConfig.Networking.DisableDefaultCNI = true
+	if err := patch(&kindConfig); err != nil {
+		return err
+	}
 
 	configYAML, err = yaml.Marshal(kindConfig)
 	if err != nil {
@@ -141,36 +167,136 @@ func (b *Builder) disableDefaultCNI() error {
 	return nil
 }
 
+func (b *Builder) disableDefaultCNI() error {
+	return b.patchConfig(func(kindConfig *v1alpha4.Cluster) error {
+		kindConfig.Networking.DisableDefaultCNI = true
+		return nil
+	})
+}
+
 func (b *Builder) useIPv6Only() error {
-	if err := b.ensureConfigFile(); err != nil {
-		return err
+	return b.patchConfig(func(kindConfig *v1alpha4.Cluster) error {
+		kindConfig.Networking.IPFamily = v1alpha4.IPv6Family
+		// For Windows/OS X Docker compatibility:
+		// https://kind.sigs.k8s.io/docs/user/configuration/#ip-family
+		kindConfig.Networking.APIServerAddress = "127.0.0.1"
+		return nil
+	})
+}
+
+func (b *Builder) useDualStack() error {
+	return b.patchConfig(func(kindConfig *v1alpha4.Cluster) error {
+		kindConfig.Networking.IPFamily = v1alpha4.DualStackFamily
+		return nil
+	})
+}
+
+func (b *Builder) configureNodes() error {
+	return b.patchConfig(func(kindConfig *v1alpha4.Cluster) error {
+		return b.patchNodes(kindConfig)
+	})
+}
+
+// patchNodes sets the number of control plane and worker nodes of a kind config
+// and adds the configured labels and taints to them. When the number of nodes of
+// a role is changed, the first existing node of that role is used as a template.
+func (b *Builder) patchNodes(kindConfig *v1alpha4.Cluster) error {
+	if b.controlPlaneNodes < 0 || b.workerNodes < 0 {
+		return fmt.Errorf("node counts can't be negative: %d control plane nodes, %d worker nodes", b.controlPlaneNodes, b.workerNodes)
 	}
 
-	configYAML, err := os.ReadFile(*b.configPath)
-	if err != nil {
-		return fmt.Errorf("failed reading kind config from %s: %w", *b.configPath, err)
+	// kind defaults to a single control plane node when no nodes are configured.
+	if len(kindConfig.Nodes) == 0 {
+		kindConfig.Nodes = []v1alpha4.Node{{Role: v1alpha4.ControlPlaneRole}}
 	}
 
-	kindConfig := v1alpha4.Cluster{}
-	if err := yaml.Unmarshal(configYAML, &kindConfig); err != nil {
-		return fmt.Errorf("failed unmarshalling kind config: %w", err)
+	nodesByRole := map[v1alpha4.NodeRole][]v1alpha4.Node{}
+	for _, node := range kindConfig.Nodes {
+		role := node.Role
+		if role == "" {
+			role = v1alpha4.ControlPlaneRole
+		}
+		nodesByRole[role] = append(nodesByRole[role], node)
 	}
+	nodesByRole[v1alpha4.ControlPlaneRole] = resizeNodes(nodesByRole[v1alpha4.ControlPlaneRole], v1alpha4.ControlPlaneRole, b.controlPlaneNodes)
+	nodesByRole[v1alpha4.WorkerRole] = resizeNodes(nodesByRole[v1alpha4.WorkerRole], v1alpha4.WorkerRole, b.workerNodes)
 
-	kindConfig.Networking.IPFamily = v1alpha4.IPv6Family
-	// For Windows/OS X Docker compatibility:
-	// https://kind.sigs.k8s.io/docs/user/configuration/#ip-family
-	kindConfig.Networking.APIServerAddress = "127.0.0.1"
+	for key, labels := range b.nodeLabels {
+		nodes := nodesByRole[key.role]
+		if key.index >= len(nodes) {
+			return fmt.Errorf("can't label %s node %d: the cluster has %d %s nodes", key.role, key.index, len(nodes), key.role)
+		}
+		if nodes[key.index].Labels == nil {
+			nodes[key.index].Labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			nodes[key.index].Labels[k] = v
+		}
+	}
 
-	configYAML, err = yaml.Marshal(kindConfig)
-	if err != nil {
-		return fmt.Errorf("failed marshalling kind config: %w", err)
+	for key, taints := range b.nodeTaints {
+		nodes := nodesByRole[key.role]
+		if key.index >= len(nodes) {
+			return fmt.Errorf("can't taint %s node %d: the cluster has %d %s nodes", key.role, key.index, len(nodes), key.role)
+		}
+		patch, err := taintsPatch(key.role == v1alpha4.ControlPlaneRole && key.index == 0, taints)
+		if err != nil {
+			return err
+		}
+		nodes[key.index].KubeadmConfigPatches = append(nodes[key.index].KubeadmConfigPatches, patch)
 	}
 
-	err = os.WriteFile(*b.configPath, configYAML, 0o600) //nolint:mnd
+	kindConfig.Nodes = append(nodesByRole[v1alpha4.ControlPlaneRole], nodesByRole[v1alpha4.WorkerRole]...)
+	return nil
+}
+
+// resizeNodes provides count nodes of the given role based on the existing ones.
+// A count of 0 leaves the existing nodes unchanged.
+func resizeNodes(existing []v1alpha4.Node, role v1alpha4.NodeRole, count int) []v1alpha4.Node {
+	if count == 0 {
+		return existing
+	}
+
+	template := v1alpha4.Node{Role: role}
+	if len(existing) > 0 {
+		template = existing[0]
+	}
+
+	nodes := make([]v1alpha4.Node, 0, count)
+	for i := range count {
+		if i < len(existing) {
+			nodes = append(nodes, existing[i])
+			continue
+		}
+		// host port mappings can only be bound by one node, so they aren't copied.
+		node := template
+		node.ExtraPortMappings = nil
+		node.Labels = maps.Clone(template.Labels)
+		node.KubeadmConfigPatches = slices.Clone(template.KubeadmConfigPatches)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// taintsPatch provides a kubeadm config patch which registers a node with the
+// given taints. The first control plane node is configured by kubeadm init,
+// every other node by kubeadm join.
+func taintsPatch(initNode bool, taints []corev1.Taint) (string, error) {
+	kind := "JoinConfiguration"
+	if initNode {
+		kind = "InitConfiguration"
+	}
+
+	patch, err := yaml.Marshal(map[string]any{
+		"kind": kind,
+		"nodeRegistration": map[string]any{
+			"taints": taints,
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("failed writing kind config %s: %w", *b.configPath, err)
+		return "", fmt.Errorf("failed marshalling node taints: %w", err)
 	}
-	return nil
+	return string(patch), nil
 }
 
 // exportLogs dumps a kind cluster logs to the specified directory
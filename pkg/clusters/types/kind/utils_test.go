@@ -0,0 +1,65 @@
+package kind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestPatchNodes(t *testing.T) {
+	t.Run("default config gets the requested topology", func(t *testing.T) {
+		b := NewBuilder().
+			WithControlPlaneNodes(3).
+			WithWorkerNodes(2).
+			WithNodeLabels(v1alpha4.WorkerRole, 1, map[string]string{"zone": "b"}).
+			WithNodeTaints(v1alpha4.ControlPlaneRole, 0, corev1.Taint{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule}).
+			WithNodeTaints(v1alpha4.WorkerRole, 0, corev1.Taint{Key: "dedicated", Value: "kong", Effect: corev1.TaintEffectNoExecute})
+
+		kindConfig := v1alpha4.Cluster{}
+		require.NoError(t, b.patchNodes(&kindConfig))
+
+		roles := make([]v1alpha4.NodeRole, 0, len(kindConfig.Nodes))
+		for _, node := range kindConfig.Nodes {
+			roles = append(roles, node.Role)
+		}
+		assert.Equal(t, []v1alpha4.NodeRole{
+			v1alpha4.ControlPlaneRole, v1alpha4.ControlPlaneRole, v1alpha4.ControlPlaneRole,
+			v1alpha4.WorkerRole, v1alpha4.WorkerRole,
+		}, roles)
+
+		assert.Empty(t, kindConfig.Nodes[3].Labels)
+		assert.Equal(t, map[string]string{"zone": "b"}, kindConfig.Nodes[4].Labels)
+
+		require.Len(t, kindConfig.Nodes[0].KubeadmConfigPatches, 1)
+		assert.Contains(t, kindConfig.Nodes[0].KubeadmConfigPatches[0], "kind: InitConfiguration")
+		assert.Contains(t, kindConfig.Nodes[0].KubeadmConfigPatches[0], "value: infra")
+		assert.Empty(t, kindConfig.Nodes[1].KubeadmConfigPatches)
+		require.Len(t, kindConfig.Nodes[3].KubeadmConfigPatches, 1)
+		assert.Contains(t, kindConfig.Nodes[3].KubeadmConfigPatches[0], "kind: JoinConfiguration")
+		assert.Contains(t, kindConfig.Nodes[3].KubeadmConfigPatches[0], "effect: NoExecute")
+	})
+
+	t.Run("existing nodes are used as templates", func(t *testing.T) {
+		b := NewBuilder().WithWorkerNodes(2)
+
+		kindConfig := v1alpha4.Cluster{Nodes: []v1alpha4.Node{
+			{Role: v1alpha4.ControlPlaneRole, ExtraPortMappings: []v1alpha4.PortMapping{{ContainerPort: 80, HostPort: 8080}}},
+			{Role: v1alpha4.WorkerRole, Image: "kindest/node:custom", ExtraPortMappings: []v1alpha4.PortMapping{{ContainerPort: 443, HostPort: 8443}}},
+		}}
+		require.NoError(t, b.patchNodes(&kindConfig))
+
+		require.Len(t, kindConfig.Nodes, 3)
+		assert.Len(t, kindConfig.Nodes[0].ExtraPortMappings, 1)
+		assert.Len(t, kindConfig.Nodes[1].ExtraPortMappings, 1)
+		assert.Equal(t, "kindest/node:custom", kindConfig.Nodes[2].Image)
+		assert.Empty(t, kindConfig.Nodes[2].ExtraPortMappings)
+	})
+
+	t.Run("nodes which don't exist can't be labeled", func(t *testing.T) {
+		b := NewBuilder().WithNodeLabels(v1alpha4.WorkerRole, 0, map[string]string{"zone": "a"})
+		require.Error(t, b.patchNodes(&v1alpha4.Cluster{}))
+	})
+}
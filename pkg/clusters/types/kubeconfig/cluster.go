@@ -0,0 +1,227 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Kubeconfig Cluster
+// -----------------------------------------------------------------------------
+
+const (
+	// KubeconfigClusterType indicates that the Kubernetes cluster was provisioned
+	// outside of KTF and is accessed through a kubeconfig or *rest.Config.
+	KubeconfigClusterType clusters.Type = "kubeconfig"
+)
+
+// CleanupFunc is a function which is called by Cluster.Cleanup() to tear down
+// a cluster which KTF did not provision itself.
+type CleanupFunc func(ctx context.Context) error
+
+// Cluster is a clusters.Cluster implementation backed by any existing Kubernetes
+// cluster which can be reached with a kubeconfig (e.g. OpenShift, EKS, or a shared
+// cluster).
+type Cluster struct {
+	name     string
+	client   *kubernetes.Clientset
+	cfg      *rest.Config
+	addons   clusters.Addons
+	l        *sync.RWMutex
+	ipFamily clusters.IPFamily
+	cleanup  CleanupFunc
+}
+
+// WithName overrides the name of the cluster, which otherwise defaults to the
+// kubeconfig context name (or the API server host for a *rest.Config).
+func (c *Cluster) WithName(name string) *Cluster {
+	c.name = name
+	return c
+}
+
+// WithCleanup configures a function that will be run when the cluster is
+// cleaned up. By default Cleanup() is a no-op as KTF does not own the cluster.
+func (c *Cluster) WithCleanup(cleanup CleanupFunc) *Cluster {
+	c.cleanup = cleanup
+	return c
+}
+
+// -----------------------------------------------------------------------------
+// Kubeconfig Cluster - Cluster Implementation
+// -----------------------------------------------------------------------------
+
+func (c *Cluster) Name() string {
+	return c.name
+}
+
+func (c *Cluster) Type() clusters.Type {
+	return KubeconfigClusterType
+}
+
+func (c *Cluster) Version() (semver.Version, error) {
+	versionInfo, err := c.Client().Discovery().ServerVersion()
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return semver.Parse(strings.TrimPrefix(versionInfo.String(), "v"))
+}
+
+func (c *Cluster) Cleanup(ctx context.Context) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if c.cleanup == nil {
+		return nil
+	}
+
+	return c.cleanup(ctx)
+}
+
+func (c *Cluster) Client() kubernetes.Interface {
+	return c.client
+}
+
+func (c *Cluster) Config() *rest.Config {
+	return c.cfg
+}
+
+func (c *Cluster) GetAddon(name clusters.AddonName) (clusters.Addon, error) {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	for addonName, addon := range c.addons {
+		if addonName == name {
+			return addon, nil
+		}
+	}
+
+	return nil, fmt.Errorf("addon %s not found", name)
+}
+
+func (c *Cluster) ListAddons() []clusters.Addon {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	addonList := make([]clusters.Addon, 0, len(c.addons))
+	for _, v := range c.addons {
+		addonList = append(addonList, v)
+	}
+
+	return addonList
+}
+
+func (c *Cluster) DeployAddon(ctx context.Context, addon clusters.Addon) error {
+	c.l.Lock()
+	if _, ok := c.addons[addon.Name()]; ok {
+		c.l.Unlock()
+		return fmt.Errorf("addon component %s is already loaded into cluster %s", addon.Name(), c.Name())
+	}
+	c.addons[addon.Name()] = addon
+	c.l.Unlock()
+
+	if err := addon.Deploy(ctx, c); err != nil {
+		return err
+	}
+
+	return clusters.SaveAddonState(ctx, c, addon)
+}
+
+func (c *Cluster) DeleteAddon(ctx context.Context, addon clusters.Addon) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if _, ok := c.addons[addon.Name()]; !ok {
+		return nil
+	}
+
+	if err := addon.Delete(ctx, c); err != nil {
+		return err
+	}
+
+	if err := clusters.DeleteAddonState(ctx, c, addon.Name()); err != nil {
+		return err
+	}
+
+	delete(c.addons, addon.Name())
+
+	return nil
+}
+
+// DumpDiagnostics produces diagnostics data for the cluster at a given time.
+// It uses the provided meta string to write to meta.txt file which will allow
+// for diagnostics identification.
+// It returns the path to directory containing all the diagnostic files and an error.
+func (c *Cluster) DumpDiagnostics(ctx context.Context, meta string) (string, error) {
+	// Obtain a kubeconfig
+	kubeconfig, err := clusters.TempKubeconfig(c)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(kubeconfig.Name())
+
+	// create a tempdir
+	outDir, err := os.MkdirTemp(os.TempDir(), clusters.DiagnosticOutDirectoryPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	// there's no access to the nodes of an arbitrary cluster, so for each Pod, run kubectl logs
+	pods, err := c.Client().CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return outDir, err
+	}
+	logsDir := filepath.Join(outDir, "pod_logs")
+	err = os.Mkdir(logsDir, 0o750) //nolint:mnd
+	if err != nil {
+		return outDir, err
+	}
+	failedPods := make(map[string]error)
+	for _, pod := range pods.Items {
+		podLogOut, err := os.Create(filepath.Join(logsDir, fmt.Sprintf("%s_%s", pod.Namespace, pod.Name)))
+		if err != nil {
+			failedPods[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = err
+			continue
+		}
+		cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig.Name(), "logs", "--all-containers", "-n", pod.Namespace, pod.Name) //nolint:gosec
+		cmd.Stdout = podLogOut
+		err = cmd.Run()
+		podLogOut.Close()
+		if err != nil {
+			failedPods[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = err
+			continue
+		}
+	}
+	if len(failedPods) > 0 {
+		failedPodOut, err := os.Create(filepath.Join(outDir, "pod_logs_failures.txt"))
+		if err != nil {
+			return outDir, err
+		}
+		defer failedPodOut.Close()
+		for failed, reason := range failedPods {
+			_, err = fmt.Fprintf(failedPodOut, "%s: %v\n", failed, reason)
+			if err != nil {
+				return outDir, err
+			}
+		}
+	}
+
+	err = clusters.DumpDiagnostics(ctx, c, meta, outDir)
+
+	return outDir, err
+}
+
+func (c *Cluster) IPFamily() clusters.IPFamily {
+	return c.ipFamily
+}
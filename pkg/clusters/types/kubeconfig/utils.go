@@ -0,0 +1,155 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Public Functions - Existing Cluster
+// -----------------------------------------------------------------------------
+
+// NewFromKubeconfig provides a Cluster object for an existing cluster given the
+// path to a kubeconfig file and the name of a context within it. If the path is
+// empty the default loading rules apply (e.g. $KUBECONFIG, then ~/.kube/config),
+// and if the context is empty the kubeconfig's current context is used.
+func NewFromKubeconfig(path, contextName string) (*Cluster, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if path != "" {
+		loadingRules.ExplicitPath = path
+	}
+	clientCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	)
+
+	rawCfg, err := clientCfg.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	name := contextName
+	if name == "" {
+		name = rawCfg.CurrentContext
+	}
+
+	cfg, err := clientCfg.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client config for context %q: %w", name, err)
+	}
+
+	cluster, err := NewFromRestConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.WithName(name), nil
+}
+
+// NewFromRestConfig provides a Cluster object for an existing cluster given a
+// *rest.Config which can be used to access it.
+func NewFromRestConfig(cfg *rest.Config) (*Cluster, error) {
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ipFamilyDetectionTimeout)
+	defer cancel()
+	ipFamily, err := detectIPFamily(ctx, kc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect IP family of cluster %s: %w", cfg.Host, err)
+	}
+
+	// rehydrate any addons which were deployed to the cluster by another process.
+	addons, err := clusters.LoadAddons(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cluster{
+		name:     cfg.Host,
+		client:   kc,
+		cfg:      cfg,
+		addons:   addons,
+		l:        &sync.RWMutex{},
+		ipFamily: ipFamily,
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Private Consts & Vars
+// -----------------------------------------------------------------------------
+
+const (
+	// ipFamilyDetectionTimeout is the maximum amount of time to wait for the API
+	// server to provide the information needed to determine the IP family.
+	ipFamilyDetectionTimeout = time.Second * 30
+)
+
+// -----------------------------------------------------------------------------
+// Private Functions
+// -----------------------------------------------------------------------------
+
+// detectIPFamily determines the IP family of the cluster from its Service CIDR(s).
+// The "kubernetes" Service in the "default" namespace always exists and carries the
+// primary family, but it stays single-stack even on dual-stack clusters, so a
+// dry-run dual-stack Service is used to find out whether a secondary CIDR exists.
+func detectIPFamily(ctx context.Context, kc kubernetes.Interface) (clusters.IPFamily, error) {
+	service, err := kc.CoreV1().Services(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	primary := ipFamilyForService(service)
+
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	probe := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ktf-ip-family-probe",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: corev1.ServiceSpec{
+			IPFamilyPolicy: &requireDualStack,
+			Ports:          []corev1.ServicePort{{Port: 80}}, //nolint:mnd
+		},
+	}
+	_, err = kc.CoreV1().Services(metav1.NamespaceDefault).Create(ctx, probe, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		// the API server rejects dual-stack services on single-stack clusters, and
+		// we may lack permissions to create services at all on a shared cluster:
+		// either way the primary family is the best information available.
+		return primary, nil
+	}
+
+	return clusters.Dual, nil
+}
+
+// ipFamilyForService reports the IP family of the given Service.
+func ipFamilyForService(service *corev1.Service) clusters.IPFamily {
+	var hasIPv4, hasIPv6 bool
+	for _, family := range service.Spec.IPFamilies {
+		switch family {
+		case corev1.IPv4Protocol:
+			hasIPv4 = true
+		case corev1.IPv6Protocol:
+			hasIPv6 = true
+		}
+	}
+
+	switch {
+	case hasIPv4 && hasIPv6:
+		return clusters.Dual
+	case hasIPv6:
+		return clusters.IPv6
+	default:
+		return clusters.IPv4
+	}
+}
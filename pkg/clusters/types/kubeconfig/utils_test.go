@@ -0,0 +1,81 @@
+package kubeconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+func TestDetectIPFamily(t *testing.T) {
+	kubernetesService := func(families ...corev1.IPFamily) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kubernetes",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: corev1.ServiceSpec{
+				IPFamilies: families,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		service         *corev1.Service
+		rejectDualStack bool
+		expected        clusters.IPFamily
+	}{
+		{
+			name:            "ipv4 only",
+			service:         kubernetesService(corev1.IPv4Protocol),
+			rejectDualStack: true,
+			expected:        clusters.IPv4,
+		},
+		{
+			name:            "ipv6 only",
+			service:         kubernetesService(corev1.IPv6Protocol),
+			rejectDualStack: true,
+			expected:        clusters.IPv6,
+		},
+		{
+			name:            "no families reported defaults to ipv4",
+			service:         kubernetesService(),
+			rejectDualStack: true,
+			expected:        clusters.IPv4,
+		},
+		{
+			name:     "dual-stack services are accepted",
+			service:  kubernetesService(corev1.IPv4Protocol),
+			expected: clusters.Dual,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := fake.NewClientset(tc.service)
+			if tc.rejectDualStack {
+				kc.PrependReactor("create", "services", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("Cluster is not configured for dual-stack")
+				})
+			}
+
+			ipFamily, err := detectIPFamily(context.Background(), kc)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ipFamily)
+		})
+	}
+
+	t.Run("missing kubernetes service", func(t *testing.T) {
+		_, err := detectIPFamily(context.Background(), fake.NewClientset())
+		require.Error(t, err)
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/google/uuid"
@@ -27,6 +29,10 @@ type Builder struct {
 	kubernetesVersion *semver.Version
 	calicoCNI         bool
 	ipv6Only          bool
+	dualStack         bool
+	failurePolicy     *clusters.FailurePolicy
+	observers         observers
+	hooks             hooks
 }
 
 // NewBuilder generates a new empty Builder for creating Environments.
@@ -85,12 +91,62 @@ func (b *Builder) WithIPv6Only() *Builder {
 	return b
 }
 
+// WithDualStack configures KIND to use both IPv4 and IPv6.
+func (b *Builder) WithDualStack() *Builder {
+	b.dualStack = true
+	return b
+}
+
+// WithFailurePolicy configures which states of the addons' objects are
+// considered unrecoverable failures by the environment's Ready and WaitForReady
+// methods, instead of clusters.DefaultFailurePolicy.
+func (b *Builder) WithFailurePolicy(policy clusters.FailurePolicy) *Builder {
+	b.failurePolicy = &policy
+	return b
+}
+
+// WithObserver adds an Observer which receives the lifecycle events of the
+// environment. If no observers are provided the events are ignored, except that
+// the location of diagnostics dumped by WaitForReady is printed to stdout.
+func (b *Builder) WithObserver(observer Observer) *Builder {
+	b.observers = append(b.observers, observer)
+	return b
+}
+
+// WithPreAddonDeployHook adds a hook which is called with each addon before
+// it is deployed.
+func (b *Builder) WithPreAddonDeployHook(hook AddonHook) *Builder {
+	b.hooks.preAddonDeploy = append(b.hooks.preAddonDeploy, hook)
+	return b
+}
+
+// WithPostAddonDeployHook adds a hook which is called with each addon after
+// it was deployed successfully.
+func (b *Builder) WithPostAddonDeployHook(hook AddonHook) *Builder {
+	b.hooks.postAddonDeploy = append(b.hooks.postAddonDeploy, hook)
+	return b
+}
+
+// WithPreCleanupHook adds a hook which is called before the environment is
+// cleaned up.
+func (b *Builder) WithPreCleanupHook(hook CleanupHook) *Builder {
+	b.hooks.preCleanup = append(b.hooks.preCleanup, hook)
+	return b
+}
+
 // Build is a blocking call to construct the configured Environment and it's
 // underlying Kubernetes cluster. The amount of time that it blocks depends
 // entirely on the underlying clusters.Cluster implementation that was requested.
+// Addons which are already loaded in an existing cluster are kept as they are
+// instead of being deployed again.
 func (b *Builder) Build(ctx context.Context) (env Environment, err error) {
 	var cluster clusters.Cluster
 
+	observer := b.observers
+	if len(observer) == 0 {
+		observer = observers{diagnosticsObserver{w: os.Stdout}}
+	}
+
 	if b.calicoCNI && b.existingCluster != nil {
 		return nil, fmt.Errorf("trying to deploy Calico CNI on an existing cluster is not currently supported")
 	}
@@ -99,6 +155,10 @@ func (b *Builder) Build(ctx context.Context) (env Environment, err error) {
 		return nil, fmt.Errorf("trying to configure IPv6 only on an existing cluster is not currently supported")
 	}
 
+	if b.dualStack && b.existingCluster != nil {
+		return nil, fmt.Errorf("trying to configure dual-stack on an existing cluster is not currently supported")
+	}
+
 	if b.existingCluster != nil && b.clusterBuilder != nil {
 		return nil, fmt.Errorf("Environment cannot specify both existingCluster and clusterBuilder")
 	}
@@ -114,10 +174,13 @@ func (b *Builder) Build(ctx context.Context) (env Environment, err error) {
 		if b.kubernetesVersion != nil {
 			return nil, fmt.Errorf("can't provide kubernetes version when providing a cluster builder")
 		}
+		observer.ClusterCreating(b.Name)
+		start := time.Now()
 		cluster, err = b.clusterBuilder.Build(ctx)
 		if err != nil {
 			return nil, err
 		}
+		observer.ClusterCreated(cluster, time.Since(start))
 	default:
 		builder := kind.NewBuilder().WithName(b.Name)
 		if b.kubernetesVersion != nil {
@@ -129,10 +192,16 @@ func (b *Builder) Build(ctx context.Context) (env Environment, err error) {
 		if b.ipv6Only {
 			builder.WithIPv6Only()
 		}
+		if b.dualStack {
+			builder.WithDualStack()
+		}
+		observer.ClusterCreating(b.Name)
+		start := time.Now()
 		cluster, err = builder.Build(ctx)
 		if err != nil {
 			return nil, err
 		}
+		observer.ClusterCreated(cluster, time.Since(start))
 	}
 	// Ensure that whole cluster is cleaned up if error is returned somewhere from this method.
 	defer func() {
@@ -143,69 +212,37 @@ func (b *Builder) Build(ctx context.Context) (env Environment, err error) {
 		}
 	}()
 
-	// determine the addon dependencies of the cluster before building
-	requiredAddons := make(map[string][]string)
-	for _, addon := range b.addons {
-		for _, dependency := range addon.Dependencies(ctx, cluster) {
-			requiredAddons[string(dependency)] = append(requiredAddons[string(dependency)], string(addon.Name()))
-		}
+	// build the addon dependency graph up front so that missing dependencies
+	// and dependency cycles are reported before anything is deployed.
+	addons, err := newAddonGraph(ctx, cluster, b.addons)
+	if err != nil {
+		return nil, err
 	}
 
-	// verify addon dependency requirements have been met
-	requiredAddonsThatAreMissing := make([]string, 0)
-	for requiredAddon, neededBy := range requiredAddons {
-		found := false
-		for _, addon := range b.addons {
-			if requiredAddon == string(addon.Name()) {
-				found = true
-				break
-			}
-		}
-		if !found {
-			requiredAddonsThatAreMissing = append(requiredAddonsThatAreMissing, fmt.Sprintf("%s (needed by %s)", requiredAddon, strings.Join(neededBy, ", ")))
-		}
-	}
-	if len(requiredAddonsThatAreMissing) != 0 {
-		return nil, fmt.Errorf("addon dependencies were not met, missing: %s", strings.Join(requiredAddonsThatAreMissing, ", "))
-	}
-
-	// run each addon deployment asynchronously and collect any errors that occur
-	addonDeploymentErrorQueue := make(chan error, len(b.addons))
-	for _, addon := range b.addons {
-		addonCopy := addon
-		go func() {
-			if err := cluster.DeployAddon(ctx, addonCopy); err != nil {
-				addonDeploymentErrorQueue <- fmt.Errorf("failed to deploy addon %s: %w", addonCopy.Name(), err)
-			}
-			addonDeploymentErrorQueue <- nil
-		}()
-	}
-
-	// wait for all deployments to report, and gather up any errors
-	collectedDeploymentErrorsCount := 0
-	addonDeploymentErrors := make([]error, 0)
-	for collectedDeploymentErrorsCount != len(b.addons) {
-		if err := <-addonDeploymentErrorQueue; err != nil {
-			addonDeploymentErrors = append(addonDeploymentErrors, err)
-		}
-		collectedDeploymentErrorsCount++
-	}
+	// deploy the addons in dependency order, independent addons in parallel
+	addonDeploymentErrors := addons.deploy(ctx, cluster, observer, b.hooks)
 
 	// if any errors occurred during deployment, report them
 	totalFailures := len(addonDeploymentErrors)
 	switch totalFailures {
 	case 0:
 		return &environment{
-			name:    b.Name,
-			cluster: cluster,
+			name:          b.Name,
+			cluster:       cluster,
+			failurePolicy: b.failurePolicy,
+			observer:      observer,
+			hooks:         b.hooks,
 		}, nil
 	case 1:
 		return nil, addonDeploymentErrors[0]
 	default:
-		errMsgs := make([]string, 0, totalFailures)
+		// wrap every error so that callers can still match them with errors.Is
+		verbs := make([]string, 0, totalFailures)
+		args := []any{totalFailures}
 		for _, err := range addonDeploymentErrors {
-			errMsgs = append(errMsgs, err.Error())
+			verbs = append(verbs, "%w")
+			args = append(args, err)
 		}
-		return nil, fmt.Errorf("%d addon deployments failed: %s", totalFailures, strings.Join(errMsgs, ", "))
+		return nil, fmt.Errorf("%d addon deployments failed: "+strings.Join(verbs, ", "), args...)
 	}
 }
@@ -34,3 +34,16 @@ type Environment interface {
 	// the caller may assume all runtime objects are resolved.
 	WaitForReady(ctx context.Context) chan error
 }
+
+// ReadinessReporter is implemented by environments which can explain why
+// they're not ready. The environments provided by Builder implement it, e.g.:
+//
+//	if reporter, ok := env.(environments.ReadinessReporter); ok {
+//		report, ready, err := reporter.ReadinessReport(ctx)
+//	}
+type ReadinessReporter interface {
+	// ReadinessReport is like Ready but explains why each object which is
+	// blocking readiness is not ready (e.g. failing conditions, container
+	// waiting reasons and recent Warning events).
+	ReadinessReport(ctx context.Context) (clusters.ReadinessReport, bool, error)
+}
@@ -0,0 +1,36 @@
+package environments_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestEnvironmentReadinessReport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "proxy"}}
+	env, err := environments.NewBuilder().
+		WithExistingCluster(fake.New()).
+		WithAddons(fake.NewAddon("a").WithReadyResults(fake.ReadyResult{WaitingForObjects: []runtime.Object{deployment}})).
+		Build(ctx)
+	require.NoError(t, err)
+
+	reporter, ok := env.(environments.ReadinessReporter)
+	require.True(t, ok, "environments provided by the builder can explain why they're not ready")
+	report, ready, err := reporter.ReadinessReport(ctx)
+	require.NoError(t, err)
+	assert.False(t, ready)
+	require.Len(t, report, 1)
+	assert.Equal(t, "Deployment kong/proxy: not ready", report[0].String())
+}
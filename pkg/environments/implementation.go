@@ -2,6 +2,7 @@ package environments
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,12 +23,17 @@ const (
 	readyHungDuration = time.Minute * 20
 
 	readyDiagnosticMeta = "WaitForReady"
+
+	readinessReportTimeout = time.Second * 10
 )
 
 // environment is the default KTF Environment used for testing Kubernetes ingress.
 type environment struct {
-	name    string
-	cluster clusters.Cluster
+	name          string
+	cluster       clusters.Cluster
+	failurePolicy *clusters.FailurePolicy
+	observer      Observer
+	hooks         hooks
 }
 
 func (env *environment) Name() string {
@@ -39,7 +45,21 @@ func (env *environment) Cluster() clusters.Cluster {
 }
 
 func (env *environment) Cleanup(ctx context.Context) error {
-	return env.Cluster().Cleanup(ctx)
+	env.observer.CleanupStarted(env.Name())
+	start := time.Now()
+
+	// the cleanup hooks can't prevent the cleanup, their errors are reported afterwards
+	var errs []error
+	for _, hook := range env.hooks.preCleanup {
+		if err := hook(ctx, env); err != nil {
+			errs = append(errs, fmt.Errorf("pre-cleanup hook failed: %w", err))
+		}
+	}
+	errs = append(errs, env.Cluster().Cleanup(ctx))
+
+	err := errors.Join(errs...)
+	env.observer.CleanupFinished(env.Name(), time.Since(start), err)
+	return err
 }
 
 func (env *environment) Ready(ctx context.Context) (waitForObjects []runtime.Object, ready bool, err error) {
@@ -70,6 +90,10 @@ func (env *environment) Ready(ctx context.Context) (waitForObjects []runtime.Obj
 		}
 	}
 
+	// addons report objects which failed unrecoverably according to the policy
+	if env.failurePolicy != nil {
+		ctx = clusters.WithFailurePolicy(ctx, *env.failurePolicy)
+	}
 	for _, addon := range env.Cluster().ListAddons() {
 		var waitForAddonObjects []runtime.Object
 		waitForAddonObjects, ready, err = addon.Ready(ctx, env.Cluster())
@@ -83,10 +107,20 @@ func (env *environment) Ready(ctx context.Context) (waitForObjects []runtime.Obj
 	return
 }
 
+func (env *environment) ReadinessReport(ctx context.Context) (clusters.ReadinessReport, bool, error) {
+	waitForObjects, ready, err := env.Ready(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	report, err := clusters.NewReadinessReport(ctx, env.Cluster(), waitForObjects)
+	return report, ready, err
+}
+
 func (env *environment) WaitForReady(ctx context.Context) chan error {
 	errs := make(chan error)
 
 	go func() {
+		start := time.Now()
 		// if the cluster fails to become ready after N minutes, assume it's likely stuck and dump a diagnostic bundle.
 		// this uses its own timer since we can't catch "go test" timeouts via the ctx.
 		hung := time.AfterFunc(readyHungDuration, func() {
@@ -95,29 +129,36 @@ func (env *environment) WaitForReady(ctx context.Context) chan error {
 				errs <- err
 				return
 			}
-			fmt.Printf("cluster not ready after %s, dumped diag to %s\n", readyHungDuration.String(), loc)
+			env.observer.DiagnosticsDumped(loc, fmt.Sprintf("cluster not ready after %s", readyHungDuration.String()))
 		})
 		waitForObjects := make([]runtime.Object, 0)
+		reported := -1
 		for {
 			select {
 			case <-ctx.Done():
-				errs <- fmt.Errorf("context done before environment was ready (remaining objects %+v): %w", waitForObjects, ctx.Err())
+				errs <- fmt.Errorf("context done before environment was ready (remaining objects:\n%s\n): %w", env.reportObjects(waitForObjects), ctx.Err())
 				hung.Stop()
 				loc, err := env.Cluster().DumpDiagnostics(ctx, readyDiagnosticMeta)
 				if err != nil {
 					errs <- err
 					return
 				}
-				fmt.Printf("cluster not ready before context completed, dumped diag to %s\n", loc)
+				env.observer.DiagnosticsDumped(loc, "cluster not ready before context completed")
 				return
 			default:
 				var ready bool
 				var err error
 				waitForObjects, ready, err = env.Ready(ctx)
 				if err != nil {
+					hung.Stop()
 					errs <- err
 					return
 				}
+				// only report progress when it changes, not on every check
+				if len(waitForObjects) != reported {
+					reported = len(waitForObjects)
+					env.observer.ReadinessProgress(waitForObjects, time.Since(start))
+				}
 				if ready {
 					errs <- nil
 					hung.Stop()
@@ -136,3 +177,16 @@ func (env *environment) WaitForReady(ctx context.Context) chan error {
 
 	return errs
 }
+
+// reportObjects provides a readiness report of the given objects once the
+// context of WaitForReady is done, so it uses its own context.
+func (env *environment) reportObjects(objects []runtime.Object) string {
+	ctx, cancel := context.WithTimeout(context.Background(), readinessReportTimeout)
+	defer cancel()
+
+	report, err := clusters.NewReadinessReport(ctx, env.Cluster(), objects)
+	if err != nil {
+		return fmt.Sprintf("%+v (failed to explain why they're not ready: %s)", objects, err)
+	}
+	return report.String()
+}
@@ -0,0 +1,226 @@
+package environments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Public Types - Environment Observers
+// -----------------------------------------------------------------------------
+
+// Observer receives the lifecycle events of an Environment, e.g. to log its
+// progress or to report timings. Addons are deployed in parallel, so observers
+// must be safe for concurrent use.
+type Observer interface {
+	// ClusterCreating is called before the cluster of the named environment
+	// is created. It's not called when the environment uses an existing
+	// cluster.
+	ClusterCreating(name string)
+
+	// ClusterCreated is called once the environment's cluster was created.
+	ClusterCreated(cluster clusters.Cluster, duration time.Duration)
+
+	// AddonDeployStarted is called once the dependencies of an addon are
+	// ready, before the addon is deployed.
+	AddonDeployStarted(addon clusters.AddonName)
+
+	// AddonDeployFinished is called once an addon was deployed successfully.
+	AddonDeployFinished(addon clusters.AddonName, duration time.Duration)
+
+	// AddonDeployFailed is called when an addon failed to deploy, or was
+	// skipped because one of its dependencies failed.
+	AddonDeployFailed(addon clusters.AddonName, duration time.Duration, err error)
+
+	// ReadinessProgress is called by WaitForReady whenever the number of
+	// objects the environment is waiting for changes, and once more with no
+	// remaining objects when the environment is ready.
+	ReadinessProgress(remaining []runtime.Object, elapsed time.Duration)
+
+	// DiagnosticsDumped is called when diagnostics were dumped because the
+	// environment failed to become ready.
+	DiagnosticsDumped(location string, reason string)
+
+	// CleanupStarted is called before the environment is cleaned up.
+	CleanupStarted(name string)
+
+	// CleanupFinished is called once the environment was cleaned up, with the
+	// cleanup error if there was one.
+	CleanupFinished(name string, duration time.Duration, err error)
+}
+
+// NopObserver is an Observer which ignores every event. It can be embedded by
+// observers which are only interested in some of the events.
+type NopObserver struct{}
+
+func (NopObserver) ClusterCreating(string)                                     {}
+func (NopObserver) ClusterCreated(clusters.Cluster, time.Duration)             {}
+func (NopObserver) AddonDeployStarted(clusters.AddonName)                      {}
+func (NopObserver) AddonDeployFinished(clusters.AddonName, time.Duration)      {}
+func (NopObserver) AddonDeployFailed(clusters.AddonName, time.Duration, error) {}
+func (NopObserver) ReadinessProgress([]runtime.Object, time.Duration)          {}
+func (NopObserver) DiagnosticsDumped(string, string)                           {}
+func (NopObserver) CleanupStarted(string)                                      {}
+func (NopObserver) CleanupFinished(string, time.Duration, error)               {}
+
+// AddonHook is called with each addon of an environment before (or after) it
+// is deployed. Returning an error fails the deployment of the addon.
+type AddonHook func(ctx context.Context, cluster clusters.Cluster, addon clusters.Addon) error
+
+// CleanupHook is called before an environment is cleaned up, e.g. to collect
+// logs. Returning an error doesn't prevent the cleanup, but it's returned by
+// Cleanup.
+type CleanupHook func(ctx context.Context, env Environment) error
+
+// -----------------------------------------------------------------------------
+// Public Functions - Environment Observers
+// -----------------------------------------------------------------------------
+
+// NewPrintObserver provides an Observer which prints a line to the writer for
+// each event.
+func NewPrintObserver(w io.Writer) Observer {
+	return &printObserver{w: w}
+}
+
+// -----------------------------------------------------------------------------
+// Private Types - Environment Observers
+// -----------------------------------------------------------------------------
+
+// diagnosticsObserver is the Observer of environments which weren't provided
+// any observers. Like environments always did, it only prints the location of
+// dumped diagnostics.
+type diagnosticsObserver struct {
+	NopObserver
+	w io.Writer
+}
+
+func (d diagnosticsObserver) DiagnosticsDumped(location string, reason string) {
+	fmt.Fprintf(d.w, "%s, dumped diag to %s\n", reason, location)
+}
+
+// printObserver is the Observer provided by NewPrintObserver.
+type printObserver struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func (p *printObserver) printf(format string, args ...any) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	fmt.Fprintf(p.w, format+"\n", args...)
+}
+
+func (p *printObserver) ClusterCreating(name string) {
+	p.printf("creating cluster %s", name)
+}
+
+func (p *printObserver) ClusterCreated(cluster clusters.Cluster, duration time.Duration) {
+	p.printf("created %s cluster %s in %s", cluster.Type(), cluster.Name(), duration.Round(time.Second))
+}
+
+func (p *printObserver) AddonDeployStarted(addon clusters.AddonName) {
+	p.printf("deploying addon %s", addon)
+}
+
+func (p *printObserver) AddonDeployFinished(addon clusters.AddonName, duration time.Duration) {
+	p.printf("deployed addon %s in %s", addon, duration.Round(time.Second))
+}
+
+func (p *printObserver) AddonDeployFailed(addon clusters.AddonName, _ time.Duration, err error) {
+	p.printf("failed to deploy addon %s: %s", addon, err)
+}
+
+func (p *printObserver) ReadinessProgress(remaining []runtime.Object, elapsed time.Duration) {
+	if len(remaining) == 0 {
+		p.printf("environment became ready after %s", elapsed.Round(time.Second))
+		return
+	}
+	p.printf("waiting for %d objects to become ready (this can take some time)...", len(remaining))
+}
+
+func (p *printObserver) DiagnosticsDumped(location string, reason string) {
+	p.printf("%s, dumped diag to %s", reason, location)
+}
+
+func (p *printObserver) CleanupStarted(name string) {
+	p.printf("cleaning up environment %s", name)
+}
+
+func (p *printObserver) CleanupFinished(name string, duration time.Duration, err error) {
+	if err != nil {
+		p.printf("failed to clean up environment %s: %s", name, err)
+		return
+	}
+	p.printf("cleaned up environment %s in %s", name, duration.Round(time.Second))
+}
+
+// observers broadcasts events to every Observer of an environment.
+type observers []Observer
+
+func (o observers) ClusterCreating(name string) {
+	for _, observer := range o {
+		observer.ClusterCreating(name)
+	}
+}
+
+func (o observers) ClusterCreated(cluster clusters.Cluster, duration time.Duration) {
+	for _, observer := range o {
+		observer.ClusterCreated(cluster, duration)
+	}
+}
+
+func (o observers) AddonDeployStarted(addon clusters.AddonName) {
+	for _, observer := range o {
+		observer.AddonDeployStarted(addon)
+	}
+}
+
+func (o observers) AddonDeployFinished(addon clusters.AddonName, duration time.Duration) {
+	for _, observer := range o {
+		observer.AddonDeployFinished(addon, duration)
+	}
+}
+
+func (o observers) AddonDeployFailed(addon clusters.AddonName, duration time.Duration, err error) {
+	for _, observer := range o {
+		observer.AddonDeployFailed(addon, duration, err)
+	}
+}
+
+func (o observers) ReadinessProgress(remaining []runtime.Object, elapsed time.Duration) {
+	for _, observer := range o {
+		observer.ReadinessProgress(remaining, elapsed)
+	}
+}
+
+func (o observers) DiagnosticsDumped(location string, reason string) {
+	for _, observer := range o {
+		observer.DiagnosticsDumped(location, reason)
+	}
+}
+
+func (o observers) CleanupStarted(name string) {
+	for _, observer := range o {
+		observer.CleanupStarted(name)
+	}
+}
+
+func (o observers) CleanupFinished(name string, duration time.Duration, err error) {
+	for _, observer := range o {
+		observer.CleanupFinished(name, duration, err)
+	}
+}
+
+// hooks are the user hooks of an environment.
+type hooks struct {
+	preAddonDeploy  []AddonHook
+	postAddonDeploy []AddonHook
+	preCleanup      []CleanupHook
+}
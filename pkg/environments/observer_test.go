@@ -0,0 +1,166 @@
+package environments_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+// recordingObserver records the events it receives as strings.
+type recordingObserver struct {
+	environments.NopObserver
+
+	lock   sync.Mutex
+	events []string
+}
+
+func (r *recordingObserver) record(format string, args ...any) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingObserver) ClusterCreating(name string) {
+	r.record("cluster creating %s", name)
+}
+
+func (r *recordingObserver) ClusterCreated(cluster clusters.Cluster, _ time.Duration) {
+	r.record("cluster created %s", cluster.Name())
+}
+
+func (r *recordingObserver) AddonDeployStarted(addon clusters.AddonName) {
+	r.record("addon started %s", addon)
+}
+
+func (r *recordingObserver) AddonDeployFinished(addon clusters.AddonName, _ time.Duration) {
+	r.record("addon finished %s", addon)
+}
+
+func (r *recordingObserver) AddonDeployFailed(addon clusters.AddonName, _ time.Duration, _ error) {
+	r.record("addon failed %s", addon)
+}
+
+func (r *recordingObserver) ReadinessProgress(remaining []runtime.Object, _ time.Duration) {
+	r.record("readiness %d", len(remaining))
+}
+
+func (r *recordingObserver) CleanupStarted(name string) {
+	r.record("cleanup started %s", name)
+}
+
+func (r *recordingObserver) CleanupFinished(name string, _ time.Duration, err error) {
+	r.record("cleanup finished %s: %v", name, err)
+}
+
+func (r *recordingObserver) Events() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Clone(r.events)
+}
+
+func TestObserverAndHooks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	observer := &recordingObserver{}
+	hookErr := errors.New("hook failed")
+	env, err := environments.NewBuilder().
+		WithName("observed").
+		WithClusterBuilder(fake.NewBuilder().WithName("observed")).
+		WithAddons(fake.NewAddon("b").WithDependencies("a"), fake.NewAddon("a")).
+		WithObserver(observer).
+		WithPreAddonDeployHook(func(_ context.Context, _ clusters.Cluster, addon clusters.Addon) error {
+			observer.record("pre-deploy hook %s", addon.Name())
+			return nil
+		}).
+		WithPostAddonDeployHook(func(_ context.Context, _ clusters.Cluster, addon clusters.Addon) error {
+			observer.record("post-deploy hook %s", addon.Name())
+			return nil
+		}).
+		WithPreCleanupHook(func(_ context.Context, env environments.Environment) error {
+			observer.record("pre-cleanup hook %s", env.Name())
+			return hookErr
+		}).
+		Build(ctx)
+	require.NoError(t, err)
+	require.NoError(t, <-env.WaitForReady(ctx))
+	require.ErrorIs(t, env.Cleanup(ctx), hookErr, "pre-cleanup hook errors are returned")
+
+	assert.Equal(t, []string{
+		"cluster creating observed",
+		"cluster created observed",
+		"addon started a",
+		"pre-deploy hook a",
+		"post-deploy hook a",
+		"addon finished a",
+		"addon started b",
+		"pre-deploy hook b",
+		"post-deploy hook b",
+		"addon finished b",
+		"readiness 0",
+		"cleanup started observed",
+		"pre-cleanup hook observed",
+		"cleanup finished observed: pre-cleanup hook failed: hook failed",
+	}, observer.Events())
+}
+
+func TestObserverReportsFailedAddons(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	observer := &recordingObserver{}
+	hookErr := errors.New("hook failed")
+	addon := fake.NewAddon("a")
+	_, err := environments.NewBuilder().
+		WithExistingCluster(fake.New()).
+		WithAddons(addon, fake.NewAddon("b").WithDependencies("a")).
+		WithObserver(observer).
+		WithPreAddonDeployHook(func(context.Context, clusters.Cluster, clusters.Addon) error {
+			return hookErr
+		}).
+		Build(ctx)
+	require.ErrorIs(t, err, hookErr)
+	require.ErrorIs(t, err, environments.ErrDependencyFailed)
+
+	assert.Equal(t, 0, addon.DeployCalls(), "addons aren't deployed when a pre-deploy hook fails")
+	assert.Equal(t, []string{
+		"addon started a",
+		"addon failed a",
+		"addon failed b",
+	}, observer.Events())
+}
+
+func TestObserverWaitsForDependenciesBeforeStartingAddons(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	observer := &recordingObserver{}
+	readyErr := errors.New("not ready")
+	_, err := environments.NewBuilder().
+		WithExistingCluster(fake.New()).
+		WithAddons(
+			fake.NewAddon("a").WithReadyResults(fake.ReadyResult{Err: readyErr}),
+			fake.NewAddon("b").WithDependencies("a"),
+		).
+		WithObserver(observer).
+		Build(ctx)
+	require.ErrorIs(t, err, readyErr)
+
+	assert.Equal(t, []string{
+		"addon started a",
+		"addon finished a",
+		"addon failed b",
+	}, observer.Events(), "addons are only started once their dependencies are ready")
+}
@@ -0,0 +1,215 @@
+package environments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+)
+
+// -----------------------------------------------------------------------------
+// Public Errors - Addon Scheduling
+// -----------------------------------------------------------------------------
+
+// ErrDependencyFailed indicates that an addon was not deployed because one of
+// its dependencies failed to deploy.
+var ErrDependencyFailed = errors.New("addon dependency failed")
+
+// -----------------------------------------------------------------------------
+// Private Types - Addon Scheduling
+// -----------------------------------------------------------------------------
+
+// addonGraph is the dependency graph of the addons of an environment, built
+// from Addon.Dependencies().
+type addonGraph struct {
+	addons       clusters.Addons
+	dependencies map[clusters.AddonName][]clusters.AddonName
+}
+
+// newAddonGraph builds the dependency graph of the given addons, verifying
+// that every dependency is provided and that there are no dependency cycles.
+// Addons which are already loaded in the cluster (e.g. an existing cluster)
+// are left out of the graph, and satisfy the dependencies on them.
+func newAddonGraph(ctx context.Context, cluster clusters.Cluster, addons clusters.Addons) (*addonGraph, error) {
+	loaded := make(map[clusters.AddonName]bool)
+	for _, addon := range cluster.ListAddons() {
+		loaded[addon.Name()] = true
+	}
+
+	g := &addonGraph{
+		addons:       make(clusters.Addons, len(addons)),
+		dependencies: make(map[clusters.AddonName][]clusters.AddonName, len(addons)),
+	}
+	for name, addon := range addons {
+		if !loaded[name] {
+			g.addons[name] = addon
+		}
+	}
+
+	// determine the addon dependencies and verify they've all been provided,
+	// only the ones in the graph are waited for when deploying. The readiness
+	// of loaded addons is still checked by WaitForAddonDependencies.
+	neededBy := make(map[clusters.AddonName][]string)
+	for _, name := range g.names() {
+		for _, dependency := range g.addons[name].Dependencies(ctx, cluster) {
+			switch {
+			case g.addons[dependency] != nil:
+				g.dependencies[name] = append(g.dependencies[name], dependency)
+			case !loaded[dependency]:
+				neededBy[dependency] = append(neededBy[dependency], string(name))
+			}
+		}
+	}
+	if len(neededBy) != 0 {
+		missing := make([]string, 0, len(neededBy))
+		for dependency, addonNames := range neededBy {
+			missing = append(missing, fmt.Sprintf("%s (needed by %s)", dependency, strings.Join(addonNames, ", ")))
+		}
+		slices.Sort(missing)
+		return nil, fmt.Errorf("addon dependencies were not met, missing: %s", strings.Join(missing, ", "))
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		path := make([]string, 0, len(cycle))
+		for _, name := range cycle {
+			path = append(path, string(name))
+		}
+		return nil, fmt.Errorf("addon dependency cycle detected: %s", strings.Join(path, " -> "))
+	}
+
+	return g, nil
+}
+
+// names provides the names of the addons in the graph in a stable order.
+func (g *addonGraph) names() []clusters.AddonName {
+	names := make([]clusters.AddonName, 0, len(g.addons))
+	for name := range g.addons {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// findCycle provides the path of the first dependency cycle found in the
+// graph (starting and ending with the same addon), or nil if it's acyclic.
+func (g *addonGraph) findCycle() []clusters.AddonName {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[clusters.AddonName]int, len(g.addons))
+	var path []clusters.AddonName
+
+	var visit func(name clusters.AddonName) []clusters.AddonName
+	visit = func(name clusters.AddonName) []clusters.AddonName {
+		switch state[name] {
+		case visiting:
+			start := slices.Index(path, name)
+			return append(slices.Clone(path[start:]), name)
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range g.dependencies[name] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range g.names() {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// deploy deploys every addon in the graph to the cluster. Each addon is
+// deployed as soon as its dependencies are deployed and ready, so independent
+// addons are deployed in parallel. If an addon fails to deploy its dependents
+// are skipped with an error wrapping ErrDependencyFailed. The observer is
+// notified of each deployment and the hooks are called around it.
+func (g *addonGraph) deploy(ctx context.Context, cluster clusters.Cluster, observer Observer, hooks hooks) []error {
+	type result struct {
+		done chan struct{}
+		err  error
+	}
+	results := make(map[clusters.AddonName]*result, len(g.addons))
+	for name := range g.addons {
+		results[name] = &result{done: make(chan struct{})}
+	}
+
+	for name, addon := range g.addons {
+		go func() {
+			r := results[name]
+			defer close(r.done)
+
+			for _, dependency := range g.dependencies[name] {
+				<-results[dependency].done
+				if results[dependency].err != nil {
+					r.err = fmt.Errorf("skipped addon %s because %s failed: %w", name, dependency, ErrDependencyFailed)
+					observer.AddonDeployFailed(name, 0, r.err)
+					return
+				}
+			}
+
+			if err := clusters.WaitForAddonDependencies(ctx, cluster, addon); err != nil {
+				r.err = fmt.Errorf("failed to deploy addon %s: %w", name, err)
+				observer.AddonDeployFailed(name, 0, r.err)
+				return
+			}
+
+			observer.AddonDeployStarted(name)
+			start := time.Now()
+			if err := deployAddon(ctx, cluster, addon, hooks); err != nil {
+				r.err = fmt.Errorf("failed to deploy addon %s: %w", name, err)
+				observer.AddonDeployFailed(name, time.Since(start), r.err)
+				return
+			}
+			observer.AddonDeployFinished(name, time.Since(start))
+		}()
+	}
+
+	var errs []error
+	for _, name := range g.names() {
+		<-results[name].done
+		if err := results[name].err; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// deployAddon deploys the addon, calling the hooks before and after the
+// deployment.
+func deployAddon(ctx context.Context, cluster clusters.Cluster, addon clusters.Addon, hooks hooks) error {
+	for _, hook := range hooks.preAddonDeploy {
+		if err := hook(ctx, cluster, addon); err != nil {
+			return fmt.Errorf("pre-deploy hook failed: %w", err)
+		}
+	}
+
+	if err := cluster.DeployAddon(ctx, addon); err != nil {
+		return err
+	}
+
+	for _, hook := range hooks.postAddonDeploy {
+		if err := hook(ctx, cluster, addon); err != nil {
+			return fmt.Errorf("post-deploy hook failed: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package environments_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestBuildDeploysAddonsInDependencyOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cluster := fake.New()
+	_, err := environments.NewBuilder().
+		WithExistingCluster(cluster).
+		WithAddons(
+			fake.NewAddon("c").WithDependencies("b"),
+			fake.NewAddon("b").WithDependencies("a"),
+			fake.NewAddon("a"),
+		).
+		Build(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []clusters.AddonName{"a", "b", "c"}, cluster.DeployAddonCalls())
+}
+
+func TestBuildDetectsDependencyCycles(t *testing.T) {
+	cluster := fake.New()
+	_, err := environments.NewBuilder().
+		WithExistingCluster(cluster).
+		WithAddons(
+			fake.NewAddon("a").WithDependencies("b"),
+			fake.NewAddon("b").WithDependencies("c"),
+			fake.NewAddon("c").WithDependencies("a"),
+			fake.NewAddon("d"),
+		).
+		Build(context.Background())
+	require.EqualError(t, err, "addon dependency cycle detected: a -> b -> c -> a")
+	assert.Empty(t, cluster.DeployAddonCalls(), "nothing is deployed when there's a cycle")
+}
+
+func TestBuildReportsMissingDependencies(t *testing.T) {
+	_, err := environments.NewBuilder().
+		WithExistingCluster(fake.New()).
+		WithAddons(fake.NewAddon("a").WithDependencies("missing")).
+		Build(context.Background())
+	require.EqualError(t, err, "addon dependencies were not met, missing: missing (needed by a)")
+}
+
+func TestBuildKeepsLoadedAddons(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cluster := fake.New()
+	require.NoError(t, cluster.DeployAddon(ctx, fake.NewAddon("loaded")))
+	require.NoError(t, cluster.DeployAddon(ctx, fake.NewAddon("dependency")))
+
+	requested := fake.NewAddon("loaded")
+	_, err := environments.NewBuilder().
+		WithExistingCluster(cluster).
+		WithAddons(requested, fake.NewAddon("a").WithDependencies("dependency")).
+		Build(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, requested.DeployCalls())
+	assert.Equal(t, []clusters.AddonName{"loaded", "dependency", "a"}, cluster.DeployAddonCalls())
+}
+
+func TestBuildSkipsDependentsOfFailedAddons(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	deployErr := errors.New("deployment failed")
+	cluster := fake.New()
+	dependent := fake.NewAddon("dependent").WithDependencies("failing")
+	transitive := fake.NewAddon("transitive").WithDependencies("dependent")
+	independent := fake.NewAddon("independent")
+
+	_, err := environments.NewBuilder().
+		WithExistingCluster(cluster).
+		WithAddons(fake.NewAddon("failing").WithDeployError(deployErr), dependent, transitive, independent).
+		Build(ctx)
+	require.ErrorIs(t, err, deployErr)
+	require.ErrorIs(t, err, environments.ErrDependencyFailed)
+	assert.ErrorContains(t, err, "3 addon deployments failed")
+
+	assert.Equal(t, 0, dependent.DeployCalls())
+	assert.Equal(t, 0, transitive.DeployCalls())
+	assert.Equal(t, 1, independent.DeployCalls())
+}
@@ -0,0 +1,503 @@
+package environments
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/argocd"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/certmanager"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/httpbin"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/istio"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kongargo"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kuma"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/registry"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+)
+
+// -----------------------------------------------------------------------------
+// Environment Spec - Public Types
+// -----------------------------------------------------------------------------
+
+const (
+	// SpecAPIVersion is the version of the environment spec schema.
+	SpecAPIVersion = "ktf.konghq.com/v1alpha1"
+
+	// SpecKind is the kind of the environment spec.
+	SpecKind = "Environment"
+)
+
+// Spec is a declarative description of an Environment, usually loaded from
+// a YAML file with LoadSpecFile, e.g.:
+//
+//	apiVersion: ktf.konghq.com/v1alpha1
+//	kind: Environment
+//	name: my-env
+//	cluster:
+//	  type: kind
+//	  kubernetesVersion: 1.35.0
+//	  ipFamily: dual
+//	addons:
+//	- name: metallb
+//	- name: kong
+//	  kong:
+//	    image: kong:3.9
+//	    chartVersion: 2.48.0
+//	    valuesFiles:
+//	    - kong-values.yaml
+//	    customPlugins:
+//	    - name: myplugin
+//	      path: plugins/myplugin
+//	    helmValues:
+//	      podAnnotations:
+//	        example.com/owner: my-team
+//	    values:
+//	      proxy.http.enabled: "true"
+type Spec struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Name is the name of the environment, a name is generated if omitted.
+	Name string `json:"name,omitempty"`
+
+	// Cluster configures the cluster of the environment.
+	Cluster ClusterSpec `json:"cluster,omitempty"`
+
+	// Addons lists the addons to deploy to the cluster.
+	Addons []AddonSpec `json:"addons,omitempty"`
+}
+
+// ClusterSpec describes the cluster of an Environment.
+type ClusterSpec struct {
+	// Type is the cluster type, either "kind" (the default) or "k3d".
+	Type clusters.Type `json:"type,omitempty"`
+
+	// KubernetesVersion is the Kubernetes version to deploy, the default of
+	// the cluster type is used if omitted.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// CNI is the CNI to use instead of the default one, only "calico" is
+	// supported (kind only).
+	CNI string `json:"cni,omitempty"`
+
+	// IPFamily is the IP family of the cluster, "ipv4" (the default), "ipv6"
+	// or "dual" (kind only).
+	IPFamily clusters.IPFamily `json:"ipFamily,omitempty"`
+
+	// ControlPlaneNodes is the number of control plane nodes (kind only).
+	ControlPlaneNodes int `json:"controlPlaneNodes,omitempty"`
+
+	// WorkerNodes is the number of worker nodes (kind) or agents (k3d).
+	WorkerNodes int `json:"workerNodes,omitempty"`
+}
+
+// AddonSpec describes an addon of an Environment.
+type AddonSpec struct {
+	// Name is the name of the addon, e.g. "metallb" or "kong".
+	Name clusters.AddonName `json:"name"`
+
+	// Version is the version of the addon, for the addons which support it
+	// (argocd, cert-manager, istio, kuma and registry).
+	Version string `json:"version,omitempty"`
+
+	// Kong configures the kong addon.
+	Kong *KongAddonSpec `json:"kong,omitempty"`
+}
+
+// KongAddonSpec describes the builder options of the kong addon.
+type KongAddonSpec struct {
+	// Name is the name of the addon, needed to deploy more than one kong addon.
+	Name string `json:"name,omitempty"`
+
+	// Namespace is the namespace to deploy Kong to.
+	Namespace string `json:"namespace,omitempty"`
+
+	// ChartVersion is the version of the Kong helm chart.
+	ChartVersion string `json:"chartVersion,omitempty"`
+
+	// Image is the Kong Gateway image, e.g. "kong:3.9".
+	Image string `json:"image,omitempty"`
+
+	// ControllerImage is the Kong Ingress Controller image.
+	ControllerImage string `json:"controllerImage,omitempty"`
+
+	// ControllerDisabled deploys only the Kong Gateway.
+	ControllerDisabled bool `json:"controllerDisabled,omitempty"`
+
+	// DBMode is the database mode, "off" (DB-less, the default) or "postgres".
+	DBMode string `json:"dbMode,omitempty"`
+
+	// Enterprise deploys Kong Gateway Enterprise, the license is read from
+	// the KONG_LICENSE_DATA environment variable.
+	Enterprise bool `json:"enterprise,omitempty"`
+
+	// SuperAdminPassword is the enterprise super admin password.
+	SuperAdminPassword string `json:"superAdminPassword,omitempty"`
+
+	// LogLevel is the Kong Gateway log level.
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// ProxyServiceType is the type of the proxy Services.
+	ProxyServiceType corev1.ServiceType `json:"proxyServiceType,omitempty"`
+
+	// AdminServiceLoadBalancer exposes the admin API with a LoadBalancer Service.
+	AdminServiceLoadBalancer bool `json:"adminServiceLoadBalancer,omitempty"`
+
+	// EnvVars are kong.conf settings (lowercase, without the KONG_ prefix).
+	EnvVars map[string]string `json:"envVars,omitempty"`
+
+	// CustomPlugins are custom Lua plugins loaded from local directories.
+	CustomPlugins []KongCustomPluginSpec `json:"customPlugins,omitempty"`
+
+	// ValuesFiles are paths of helm values files, merged over the addon's own
+	// values in order. LoadSpecFile resolves them relative to the spec file.
+	ValuesFiles []string `json:"valuesFiles,omitempty"`
+
+	// HelmValues are structured helm values, merged over the addon's own
+	// values like a values file. They take precedence over ValuesFiles.
+	HelmValues map[string]any `json:"helmValues,omitempty"`
+
+	// Values are extra helm values, each set with --set key=value. They take
+	// precedence over HelmValues.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// KongCustomPluginSpec describes a custom Lua plugin of the kong addon.
+type KongCustomPluginSpec struct {
+	// Name is the name of the plugin.
+	Name string `json:"name"`
+
+	// Path is the local directory with the plugin's Lua files. LoadSpecFile
+	// resolves it relative to the spec file.
+	Path string `json:"path"`
+}
+
+// -----------------------------------------------------------------------------
+// Environment Spec - Public Functions
+// -----------------------------------------------------------------------------
+
+// LoadSpec reads an environment Spec in YAML (or JSON) format. Unknown fields
+// are rejected.
+func LoadSpec(r io.Reader) (*Spec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment spec: %w", err)
+	}
+
+	spec := &Spec{}
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse environment spec: %w", err)
+	}
+	if spec.APIVersion != SpecAPIVersion || spec.Kind != SpecKind {
+		return nil, fmt.Errorf("unsupported environment spec %s/%s, expected %s/%s", spec.APIVersion, spec.Kind, SpecAPIVersion, SpecKind)
+	}
+
+	return spec, nil
+}
+
+// LoadSpecFile reads an environment Spec from the given file. Relative paths
+// of the spec (e.g. kong values files) are relative to the file's directory.
+func LoadSpecFile(filename string) (*Spec, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment spec %s: %w", filename, err)
+	}
+	spec, err := LoadSpec(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(filename)
+	for _, addon := range spec.Addons {
+		if addon.Kong == nil {
+			continue
+		}
+		for i, valuesFile := range addon.Kong.ValuesFiles {
+			addon.Kong.ValuesFiles[i] = relativeTo(dir, valuesFile)
+		}
+		for i := range addon.Kong.CustomPlugins {
+			addon.Kong.CustomPlugins[i].Path = relativeTo(dir, addon.Kong.CustomPlugins[i].Path)
+		}
+	}
+	return spec, nil
+}
+
+// NewBuilderFromSpec provides a Builder configured as described by the spec.
+func NewBuilderFromSpec(spec *Spec) (*Builder, error) {
+	builder := NewBuilder()
+	if spec.Name != "" {
+		builder.WithName(spec.Name)
+	}
+
+	clusterBuilder, err := spec.Cluster.clusterBuilder(builder.Name)
+	if err != nil {
+		return nil, err
+	}
+	builder.WithClusterBuilder(clusterBuilder)
+
+	for _, addonSpec := range spec.Addons {
+		addon, err := addonSpec.addon()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := builder.addons[addon.Name()]; ok {
+			return nil, fmt.Errorf("addon %s was provided more than once", addon.Name())
+		}
+		builder.WithAddons(addon)
+	}
+
+	return builder, nil
+}
+
+// -----------------------------------------------------------------------------
+// Environment Spec - Private
+// -----------------------------------------------------------------------------
+
+func (s ClusterSpec) clusterBuilder(name string) (clusters.Builder, error) {
+	var version *semver.Version
+	if s.KubernetesVersion != "" {
+		v, err := semver.Parse(strings.TrimPrefix(s.KubernetesVersion, "v"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubernetesVersion %s: %w", s.KubernetesVersion, err)
+		}
+		version = &v
+	}
+
+	switch s.Type {
+	case "", kind.KindClusterType:
+		builder := kind.NewBuilder().WithName(name)
+		if version != nil {
+			builder.WithClusterVersion(*version)
+		}
+		switch s.CNI {
+		case "":
+		case "calico":
+			builder.WithCalicoCNI()
+		default:
+			return nil, fmt.Errorf("unsupported cni %s, only calico is supported", s.CNI)
+		}
+		switch s.IPFamily {
+		case "", clusters.IPv4:
+		case clusters.IPv6:
+			builder.WithIPv6Only()
+		case clusters.Dual:
+			builder.WithDualStack()
+		default:
+			return nil, fmt.Errorf("unsupported ipFamily %s", s.IPFamily)
+		}
+		if s.ControlPlaneNodes > 0 {
+			builder.WithControlPlaneNodes(s.ControlPlaneNodes)
+		}
+		if s.WorkerNodes > 0 {
+			builder.WithWorkerNodes(s.WorkerNodes)
+		}
+		return builder, nil
+	case k3d.K3dClusterType:
+		if s.CNI != "" || (s.IPFamily != "" && s.IPFamily != clusters.IPv4) || s.ControlPlaneNodes > 0 {
+			return nil, fmt.Errorf("cni, ipFamily and controlPlaneNodes are not supported for %s clusters", s.Type)
+		}
+		builder := k3d.NewBuilder().WithName(name)
+		if version != nil {
+			builder.WithClusterVersion(*version)
+		}
+		if s.WorkerNodes > 0 {
+			builder.WithAgents(s.WorkerNodes)
+		}
+		return builder, nil
+	default:
+		return nil, fmt.Errorf("unsupported cluster type %s, supported types are %s and %s", s.Type, kind.KindClusterType, k3d.K3dClusterType)
+	}
+}
+
+func (s AddonSpec) addon() (clusters.Addon, error) {
+	if s.Kong != nil && s.Name != kong.AddonName {
+		return nil, fmt.Errorf("kong options were provided for addon %s", s.Name)
+	}
+
+	var version *semver.Version
+	if s.Version != "" {
+		switch s.Name {
+		case argocd.AddonName, certmanager.AddonName, istio.AddonName, kuma.AddonName, registry.AddonName:
+		default:
+			return nil, fmt.Errorf("addon %s doesn't support a version", s.Name)
+		}
+		v, err := semver.Parse(strings.TrimPrefix(s.Version, "v"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %s for addon %s: %w", s.Version, s.Name, err)
+		}
+		version = &v
+	}
+
+	switch s.Name {
+	case metallb.AddonName:
+		return metallb.New(), nil
+	case kong.AddonName:
+		if s.Kong == nil {
+			return kong.New(), nil
+		}
+		return s.Kong.addon()
+	case istio.AddonName:
+		builder := istio.NewBuilder().
+			WithGrafana().
+			WithJaeger().
+			WithKiali().
+			WithPrometheus()
+		if version != nil {
+			builder.WithVersion(*version)
+		}
+		return builder.Build(), nil
+	case httpbin.AddonName:
+		return httpbin.New(), nil
+	case certmanager.AddonName:
+		builder := certmanager.NewBuilder()
+		if version != nil {
+			builder.WithVersion(*version)
+		}
+		return builder.Build(), nil
+	case kuma.AddonName:
+		builder := kuma.NewBuilder()
+		if version != nil {
+			builder.WithVersion(*version)
+		}
+		return builder.Build(), nil
+	case argocd.AddonName:
+		builder := argocd.NewBuilder()
+		if version != nil {
+			builder.WithVersion(*version)
+		}
+		return builder.Build(), nil
+	case kongargo.AddonName:
+		return kongargo.NewBuilder().Build(), nil
+	case registry.AddonName:
+		builder := registry.NewBuilder().WithServiceTypeLoadBalancer()
+		if version != nil {
+			builder.WithVersion(*version)
+		}
+		return builder.Build(), nil
+	default:
+		return nil, fmt.Errorf("unsupported addon %s", s.Name)
+	}
+}
+
+func (s *KongAddonSpec) addon() (clusters.Addon, error) {
+	builder := kong.NewBuilder()
+	if s.Name != "" {
+		builder.WithName(s.Name)
+	}
+	if s.Namespace != "" {
+		builder.WithNamespace(s.Namespace)
+	}
+	if s.ChartVersion != "" {
+		builder.WithHelmChartVersion(s.ChartVersion)
+	}
+	if s.Image != "" {
+		repo, tag, err := splitImage(s.Image)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithProxyImage(repo, tag)
+	}
+	if s.ControllerImage != "" {
+		repo, tag, err := splitImage(s.ControllerImage)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithControllerImage(repo, tag)
+	}
+	if s.ControllerDisabled {
+		builder.WithControllerDisabled()
+	}
+
+	switch s.DBMode {
+	case "", "off", string(kong.DBLESS):
+		builder.WithDBLess()
+	case string(kong.PostgreSQL):
+		builder.WithPostgreSQL()
+	default:
+		return nil, fmt.Errorf("%s is not a valid dbMode for kong, supported modes are \"off\" (DBLESS) or \"postgres\"", s.DBMode)
+	}
+
+	if s.Enterprise {
+		licenseJSON, err := kong.GetLicenseJSONFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the kong enterprise license: %w", err)
+		}
+		builder.WithProxyEnterpriseEnabled(licenseJSON)
+	}
+	if s.SuperAdminPassword != "" {
+		builder.WithProxyEnterpriseSuperAdminPassword(s.SuperAdminPassword)
+	}
+	if s.LogLevel != "" {
+		builder.WithLogLevel(s.LogLevel)
+	}
+	if s.ProxyServiceType != "" {
+		builder.WithProxyServiceType(s.ProxyServiceType)
+	}
+	if s.AdminServiceLoadBalancer {
+		builder.WithProxyAdminServiceTypeLoadBalancer()
+	}
+	for name, value := range s.EnvVars {
+		builder.WithProxyEnvVar(name, value)
+	}
+	for _, plugin := range s.CustomPlugins {
+		// fail before the cluster is created if the directory can't be read
+		info, err := os.Stat(plugin.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom plugin %s: %w", plugin.Name, err)
+		}
+		if plugin.Name == "" || !info.IsDir() {
+			return nil, fmt.Errorf("invalid custom plugin %s: a name and a directory are required", plugin.Name)
+		}
+		builder.WithCustomPlugin(plugin.Name, plugin.Path)
+	}
+	for _, valuesFile := range s.ValuesFiles {
+		// fail before the cluster is created if the file can't be read
+		if _, err := os.Stat(valuesFile); err != nil {
+			return nil, fmt.Errorf("invalid values file: %w", err)
+		}
+		builder.WithValuesFile(valuesFile)
+	}
+	if s.HelmValues != nil {
+		builder.WithValues(s.HelmValues)
+	}
+	for name, value := range s.Values {
+		builder.WithAdditionalValue(name, value)
+	}
+
+	return builder.Build(), nil
+}
+
+// relativeTo provides the path relative to the directory, unless it's absolute.
+func relativeTo(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// splitImage splits a container image into its repository and tag, using
+// "latest" when no tag is provided.
+func splitImage(image string) (string, string, error) {
+	// the repository may include a registry port, so split on the last colon
+	// which comes after the last slash.
+	idx := strings.LastIndex(image, ":")
+	if idx == -1 || strings.LastIndex(image, "/") > idx {
+		return image, "latest", nil
+	}
+	repo, tag := image[:idx], image[idx+1:]
+	if repo == "" || tag == "" {
+		return "", "", fmt.Errorf("malformed image %s", image)
+	}
+	return repo, tag, nil
+}
@@ -0,0 +1,212 @@
+package environments
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+)
+
+func TestNewBuilderFromSpec(t *testing.T) {
+	spec, err := LoadSpec(strings.NewReader(`
+apiVersion: ktf.konghq.com/v1alpha1
+kind: Environment
+name: spec-test
+cluster:
+  kubernetesVersion: v1.35.0
+  ipFamily: dual
+  workerNodes: 2
+addons:
+- name: metallb
+- name: kong
+  kong:
+    namespace: kong-test
+    image: localhost:5000/kong:3.9
+    chartVersion: 2.48.0
+    dbMode: postgres
+    envVars:
+      router_flavor: expressions
+    helmValues:
+      podAnnotations:
+        example.com/owner: kic
+    values:
+      proxy.http.enabled: "true"
+`))
+	require.NoError(t, err)
+
+	builder, err := NewBuilderFromSpec(spec)
+	require.NoError(t, err)
+	assert.Equal(t, "spec-test", builder.Name)
+	require.IsType(t, &kind.Builder{}, builder.clusterBuilder)
+	assert.Equal(t, "spec-test", builder.clusterBuilder.(*kind.Builder).Name)
+	require.Len(t, builder.addons, 2)
+	assert.Contains(t, builder.addons, metallb.AddonName)
+
+	kongAddon, ok := builder.addons[kong.AddonName].(*kong.Addon)
+	require.True(t, ok)
+	state, err := kongAddon.State()
+	require.NoError(t, err)
+	assert.Equal(t, "2.48.0", state.Version)
+
+	var options map[string]any
+	require.NoError(t, json.Unmarshal(state.Options, &options))
+	assert.Equal(t, "kong-test", options["namespace"])
+	assert.Equal(t, "localhost:5000/kong", options["proxyImage"])
+	assert.Equal(t, "3.9", options["proxyImageTag"])
+	assert.Equal(t, string(kong.PostgreSQL), options["proxyDBMode"])
+	assert.Equal(t, map[string]any{"router_flavor": "expressions"}, options["proxyEnvVars"])
+	assert.Equal(t, map[string]any{"podAnnotations": map[string]any{"example.com/owner": "kic"}}, options["values"])
+	assert.Equal(t, map[string]any{"proxy.http.enabled": "true"}, options["additionalValues"])
+}
+
+func TestNewBuilderFromSpecKongFiles(t *testing.T) {
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte("podAnnotations:\n  example.com/owner: file\n  example.com/team: kic\n"), 0o600))
+	pluginDir := filepath.Join(dir, "myplugin")
+	require.NoError(t, os.Mkdir(pluginDir, 0o755))
+
+	builder, err := NewBuilderFromSpec(&Spec{Addons: []AddonSpec{{Name: kong.AddonName, Kong: &KongAddonSpec{
+		ValuesFiles:   []string{valuesFile},
+		CustomPlugins: []KongCustomPluginSpec{{Name: "myplugin", Path: pluginDir}},
+		HelmValues:    map[string]any{"podAnnotations": map[string]any{"example.com/owner": "spec"}},
+	}}}})
+	require.NoError(t, err)
+
+	state, err := builder.addons[kong.AddonName].(*kong.Addon).State()
+	require.NoError(t, err)
+	var options map[string]any
+	require.NoError(t, json.Unmarshal(state.Options, &options))
+	assert.Equal(t, []any{map[string]any{"name": "myplugin", "sourceDir": pluginDir}}, options["customPlugins"])
+	assert.Equal(t, map[string]any{"podAnnotations": map[string]any{"example.com/owner": "spec", "example.com/team": "kic"}}, options["values"],
+		"helmValues take precedence over values files")
+}
+
+func TestLoadSpecFileRelativePaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "specs")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "plugins", "myplugin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("podAnnotations: {}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.yaml"), []byte(`
+apiVersion: ktf.konghq.com/v1alpha1
+kind: Environment
+addons:
+- name: kong
+  kong:
+    valuesFiles:
+    - values.yaml
+    - /etc/kong/values.yaml
+    customPlugins:
+    - name: myplugin
+      path: plugins/myplugin
+`), 0o600))
+
+	spec, err := LoadSpecFile(filepath.Join(dir, "env.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "values.yaml"), "/etc/kong/values.yaml"}, spec.Addons[0].Kong.ValuesFiles,
+		"relative paths are relative to the spec file, absolute paths are kept")
+	assert.Equal(t, filepath.Join(dir, "plugins", "myplugin"), spec.Addons[0].Kong.CustomPlugins[0].Path)
+}
+
+func TestNewBuilderFromSpecK3d(t *testing.T) {
+	builder, err := NewBuilderFromSpec(&Spec{
+		APIVersion: SpecAPIVersion,
+		Kind:       SpecKind,
+		Cluster:    ClusterSpec{Type: k3d.K3dClusterType, WorkerNodes: 1},
+	})
+	require.NoError(t, err)
+	require.IsType(t, &k3d.Builder{}, builder.clusterBuilder)
+	assert.Empty(t, builder.addons)
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "unsupported version",
+			spec: "apiVersion: ktf.konghq.com/v2\nkind: Environment\n",
+			err:  "unsupported environment spec ktf.konghq.com/v2/Environment",
+		},
+		{
+			name: "unknown field",
+			spec: "apiVersion: ktf.konghq.com/v1alpha1\nkind: Environment\nclusters: {}\n",
+			err:  "failed to parse environment spec",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadSpec(strings.NewReader(tc.spec))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestNewBuilderFromSpecErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec Spec
+		err  string
+	}{
+		{
+			name: "unsupported cluster type",
+			spec: Spec{Cluster: ClusterSpec{Type: "gke"}},
+			err:  "unsupported cluster type gke",
+		},
+		{
+			name: "ip family on k3d",
+			spec: Spec{Cluster: ClusterSpec{Type: k3d.K3dClusterType, IPFamily: clusters.IPv6}},
+			err:  "not supported for k3d clusters",
+		},
+		{
+			name: "unsupported addon",
+			spec: Spec{Addons: []AddonSpec{{Name: "unknown"}}},
+			err:  "unsupported addon unknown",
+		},
+		{
+			name: "unsupported addon version",
+			spec: Spec{Addons: []AddonSpec{{Name: metallb.AddonName, Version: "1.0.0"}}},
+			err:  "addon metallb doesn't support a version",
+		},
+		{
+			name: "kong options for another addon",
+			spec: Spec{Addons: []AddonSpec{{Name: metallb.AddonName, Kong: &KongAddonSpec{}}}},
+			err:  "kong options were provided for addon metallb",
+		},
+		{
+			name: "invalid kong dbmode",
+			spec: Spec{Addons: []AddonSpec{{Name: kong.AddonName, Kong: &KongAddonSpec{DBMode: "mysql"}}}},
+			err:  "mysql is not a valid dbMode for kong",
+		},
+		{
+			name: "missing kong values file",
+			spec: Spec{Addons: []AddonSpec{{Name: kong.AddonName, Kong: &KongAddonSpec{ValuesFiles: []string{"missing.yaml"}}}}},
+			err:  "invalid values file",
+		},
+		{
+			name: "missing kong custom plugin directory",
+			spec: Spec{Addons: []AddonSpec{{Name: kong.AddonName, Kong: &KongAddonSpec{CustomPlugins: []KongCustomPluginSpec{{Name: "myplugin", Path: "missing"}}}}}},
+			err:  "invalid custom plugin myplugin",
+		},
+		{
+			name: "duplicate addon",
+			spec: Spec{Addons: []AddonSpec{{Name: metallb.AddonName}, {Name: metallb.AddonName}}},
+			err:  "addon metallb was provided more than once",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewBuilderFromSpec(&tc.spec)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Package testenv shares one testing environment across the tests of a
+// package. It's meant to be used from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		builder := environments.NewBuilder().WithAddons(kong.New())
+//		os.Exit(testenv.Run(m, builder))
+//	}
+//
+// Tests can then access the environment with testenv.Environment().
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/gke"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kubeconfig"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+// -----------------------------------------------------------------------------
+// Public Consts & Vars
+// -----------------------------------------------------------------------------
+
+const (
+	// EnvExistingCluster is the environment variable which can be set to
+	// "<type>:<name>" to run the tests against an existing cluster instead of
+	// creating one, e.g. "kind:my-cluster". The supported types are "kind",
+	// "k3d", "gke" and "kubeconfig" (for which the name is the kubeconfig
+	// context, or empty for the current context).
+	EnvExistingCluster = "KTF_TEST_CLUSTER"
+
+	// EnvKeepCluster is the environment variable which can be set to "true" in
+	// order to keep the environment's cluster (whatever its type) after the
+	// tests, e.g. to inspect it or to reuse it with EnvExistingCluster.
+	EnvKeepCluster = "KTF_TEST_KEEP_CLUSTER"
+
+	// DefaultSetupTimeout is the default amount of time allowed to build the
+	// environment and wait for it to become ready.
+	DefaultSetupTimeout = time.Minute * 20
+
+	// DefaultCleanupTimeout is the default amount of time allowed to clean up
+	// the environment after the tests.
+	DefaultCleanupTimeout = time.Minute * 5
+)
+
+// -----------------------------------------------------------------------------
+// Public Types & Functions
+// -----------------------------------------------------------------------------
+
+// Option configures Run.
+type Option func(*options)
+
+// WithSetupTimeout configures the amount of time allowed to build the
+// environment and wait for it to become ready, instead of DefaultSetupTimeout.
+func WithSetupTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.setupTimeout = timeout
+	}
+}
+
+// WithCleanupTimeout configures the amount of time allowed to clean up the
+// environment, instead of DefaultCleanupTimeout.
+func WithCleanupTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.cleanupTimeout = timeout
+	}
+}
+
+// Run builds the environment (or attaches it to the cluster provided by
+// EnvExistingCluster), waits for it to become ready and runs the tests,
+// providing their exit code. Diagnostics are dumped if any test failed and
+// the environment is cleaned up afterwards unless EnvKeepCluster is set.
+//
+// When attached to an existing cluster only the addons which were deployed
+// by the environment are deleted, the cluster itself is kept. The builder
+// must not configure the cluster in that case (e.g. WithClusterBuilder).
+func Run(m *testing.M, builder *environments.Builder, opts ...Option) int {
+	return run(m.Run, builder, opts...)
+}
+
+// Environment provides the environment shared by the tests, which is nil
+// until Run has set it up.
+func Environment() environments.Environment {
+	return env
+}
+
+// -----------------------------------------------------------------------------
+// Private Types, Vars & Functions
+// -----------------------------------------------------------------------------
+
+type options struct {
+	setupTimeout   time.Duration
+	cleanupTimeout time.Duration
+}
+
+// diagnosticsMeta is the meta information of the diagnostics dumped when tests
+// failed.
+const diagnosticsMeta = "testenv"
+
+var (
+	// env is the environment shared by the tests.
+	env environments.Environment
+
+	// existingCluster provides the cluster described by EnvExistingCluster.
+	existingCluster = newFromExisting
+)
+
+func run(runTests func() int, builder *environments.Builder, opts ...Option) int {
+	o := options{
+		setupTimeout:   DefaultSetupTimeout,
+		cleanupTimeout: DefaultCleanupTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// addons which were deployed to an existing cluster before are kept
+	var attached bool
+	var preexistingAddons []clusters.AddonName
+	if spec := os.Getenv(EnvExistingCluster); spec != "" {
+		cluster, err := existingCluster(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to use the existing cluster %s: %s\n", spec, err)
+			return 1
+		}
+		for _, addon := range cluster.ListAddons() {
+			preexistingAddons = append(preexistingAddons, addon.Name())
+		}
+		builder = builder.WithExistingCluster(cluster)
+		attached = true
+	}
+
+	var err error
+	env, err = setup(builder, o.setupTimeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up the testing environment: %s\n", err)
+		if env != nil {
+			dumpDiagnostics(o.cleanupTimeout)
+			if err := teardown(attached, preexistingAddons, o.cleanupTimeout); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to clean up the testing environment: %s\n", err)
+			}
+		}
+		return 1
+	}
+
+	code := runTests()
+	if code != 0 {
+		dumpDiagnostics(o.cleanupTimeout)
+	}
+
+	if err := teardown(attached, preexistingAddons, o.cleanupTimeout); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to clean up the testing environment: %s\n", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// setup builds the environment and waits for it to become ready. The
+// environment is provided if it was built, even if it's not ready.
+func setup(builder *environments.Builder, timeout time.Duration) (environments.Environment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	env, err := builder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return env, <-env.WaitForReady(ctx)
+}
+
+// dumpDiagnostics dumps the diagnostics of the environment's cluster.
+func dumpDiagnostics(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	loc, err := env.Cluster().DumpDiagnostics(ctx, diagnosticsMeta)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to dump diagnostics: %s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "tests failed, dumped diag to %s\n", loc)
+}
+
+// teardown cleans up the environment, unless it was requested to be kept. When
+// attached to an existing cluster only the addons deployed by the environment
+// are deleted.
+func teardown(attached bool, preexistingAddons []clusters.AddonName, timeout time.Duration) error {
+	cluster := env.Cluster()
+	if os.Getenv(EnvKeepCluster) == "true" {
+		fmt.Fprintf(os.Stderr, "keeping cluster %s, reuse it with %s=%s:%s\n", cluster.Name(), EnvExistingCluster, cluster.Type(), cluster.Name())
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if !attached {
+		return env.Cleanup(ctx)
+	}
+	for _, addon := range cluster.ListAddons() {
+		if slices.Contains(preexistingAddons, addon.Name()) {
+			continue
+		}
+		if err := cluster.DeleteAddon(ctx, addon); err != nil {
+			return fmt.Errorf("failed to delete addon %s: %w", addon.Name(), err)
+		}
+	}
+	return nil
+}
+
+// newFromExisting provides the cluster described by spec, in the format of
+// EnvExistingCluster.
+func newFromExisting(spec string) (clusters.Cluster, error) {
+	clusterType, name, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("%s must be in the format <type>:<name>, got %q", EnvExistingCluster, spec)
+	}
+
+	switch clusters.Type(clusterType) {
+	case kind.KindClusterType:
+		return kind.NewFromExisting(name)
+	case k3d.K3dClusterType:
+		return k3d.NewFromExisting(name)
+	case kubeconfig.KubeconfigClusterType:
+		return kubeconfig.NewFromKubeconfig("", name)
+	case gke.GKEClusterType:
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultSetupTimeout)
+		defer cancel()
+		return gke.NewFromExistingWithEnv(ctx, name)
+	default:
+		return nil, fmt.Errorf("unsupported cluster type %q in %s, supported types are kind, k3d, gke and kubeconfig", clusterType, EnvExistingCluster)
+	}
+}
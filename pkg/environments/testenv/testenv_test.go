@@ -0,0 +1,111 @@
+package testenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/fake"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+// clusterBuilder provides a pre-built cluster.
+type clusterBuilder struct {
+	cluster clusters.Cluster
+}
+
+func (b clusterBuilder) Build(_ context.Context) (clusters.Cluster, error) {
+	return b.cluster, nil
+}
+
+func TestRunCreatesAndCleansUpEnvironment(t *testing.T) {
+	cluster := fake.New()
+	builder := environments.NewBuilder().
+		WithClusterBuilder(clusterBuilder{cluster}).
+		WithAddons(fake.NewAddon("a"))
+
+	code := run(func() int {
+		require.NotNil(t, Environment())
+		assert.Equal(t, cluster, Environment().Cluster())
+		assert.False(t, cluster.CleanedUp(), "the environment is available while the tests run")
+		return 0
+	}, builder)
+	assert.Equal(t, 0, code)
+	assert.True(t, cluster.CleanedUp())
+}
+
+func TestRunKeepsCluster(t *testing.T) {
+	t.Setenv(EnvKeepCluster, "true")
+	cluster := fake.New()
+
+	code := run(func() int { return 1 }, environments.NewBuilder().WithClusterBuilder(clusterBuilder{cluster}))
+	assert.Equal(t, 1, code, "the exit code of the tests is provided")
+	assert.False(t, cluster.CleanedUp())
+}
+
+func TestRunAttachesToExistingCluster(t *testing.T) {
+	t.Setenv(EnvExistingCluster, "fake:existing")
+	cluster := fake.New()
+	preexisting := fake.NewAddon("preexisting")
+	require.NoError(t, cluster.DeployAddon(context.Background(), preexisting))
+	existingCluster = func(spec string) (clusters.Cluster, error) {
+		assert.Equal(t, "fake:existing", spec)
+		return cluster, nil
+	}
+	t.Cleanup(func() { existingCluster = newFromExisting })
+
+	code := run(func() int { return 0 }, environments.NewBuilder().WithAddons(fake.NewAddon("a")))
+	assert.Equal(t, 0, code)
+	assert.False(t, cluster.CleanedUp(), "existing clusters are kept")
+	assert.Equal(t, []clusters.AddonName{"a"}, cluster.DeleteAddonCalls(), "only the environment's addons are deleted")
+}
+
+func TestRunAttachesToExistingClusterWithRequestedAddons(t *testing.T) {
+	t.Setenv(EnvExistingCluster, "fake:existing")
+	cluster := fake.New()
+	preexisting := fake.NewAddon("preexisting")
+	require.NoError(t, cluster.DeployAddon(context.Background(), preexisting))
+	existingCluster = func(_ string) (clusters.Cluster, error) {
+		return cluster, nil
+	}
+	t.Cleanup(func() { existingCluster = newFromExisting })
+
+	requested := fake.NewAddon("preexisting")
+	builder := environments.NewBuilder().WithAddons(requested, fake.NewAddon("a").WithDependencies("preexisting"))
+	code := run(func() int {
+		addon, err := Environment().Cluster().GetAddon("preexisting")
+		require.NoError(t, err)
+		assert.Same(t, preexisting, addon, "the loaded addon is kept")
+		return 0
+	}, builder)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 0, requested.DeployCalls(), "addons which are already loaded aren't deployed again")
+	assert.Equal(t, []clusters.AddonName{"preexisting", "a"}, cluster.DeployAddonCalls())
+	assert.Equal(t, []clusters.AddonName{"a"}, cluster.DeleteAddonCalls(), "only the environment's addons are deleted")
+}
+
+func TestRunDoesNotRunTestsWhenSetupFails(t *testing.T) {
+	cluster := fake.New()
+	builder := environments.NewBuilder().
+		WithClusterBuilder(clusterBuilder{cluster}).
+		WithAddons(fake.NewAddon("a").WithDeployError(errors.New("deployment failed")))
+
+	code := run(func() int {
+		t.Error("tests ran although the environment failed to deploy")
+		return 0
+	}, builder)
+	assert.Equal(t, 1, code)
+	assert.True(t, cluster.CleanedUp(), "the cluster created for the environment is cleaned up")
+}
+
+func TestNewFromExistingRejectsInvalidSpecs(t *testing.T) {
+	_, err := newFromExisting("my-cluster")
+	require.ErrorContains(t, err, "must be in the format <type>:<name>")
+
+	_, err = newFromExisting("minikube:my-cluster")
+	require.ErrorContains(t, err, `unsupported cluster type "minikube"`)
+}
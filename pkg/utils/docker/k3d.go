@@ -0,0 +1,29 @@
+package docker
+
+import "fmt"
+
+// -----------------------------------------------------------------------------
+// Public Vars - K3d
+// -----------------------------------------------------------------------------
+
+var (
+	// K3dContainerPrefix provides the string prefix that k3d names all cluster containers and networks with.
+	K3dContainerPrefix = "k3d-"
+
+	// K3dServerContainerSuffix provides the string suffix that k3d names the first server container with.
+	K3dServerContainerSuffix = "-server-0"
+)
+
+// -----------------------------------------------------------------------------
+// Public Functions - K3d Helpers
+// -----------------------------------------------------------------------------
+
+// GetK3dContainerID produces the docker container ID for the first server node of the given k3d cluster by name.
+func GetK3dContainerID(clusterName string) string {
+	return fmt.Sprintf("%s%s%s", K3dContainerPrefix, clusterName, K3dServerContainerSuffix)
+}
+
+// GetK3dNetwork produces the name of the docker network that k3d creates for the given cluster by name.
+func GetK3dNetwork(clusterName string) string {
+	return fmt.Sprintf("%s%s", K3dContainerPrefix, clusterName)
+}
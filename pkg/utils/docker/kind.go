@@ -1,15 +1,32 @@
 package docker
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moby/moby/client"
+)
 
 // -----------------------------------------------------------------------------
 // Public Vars - Kind
 // -----------------------------------------------------------------------------
 
 var (
-	// KindContainerSuffix provides the string suffix that Kind names all cluster containers with.
+	// KindContainerSuffix provides the string suffix that Kind names the first control plane container with.
 	KindContainerSuffix = "-control-plane"
 
+	// KindClusterLabel is the label Kind applies to every node container, with the cluster name as its value.
+	KindClusterLabel = "io.x-k8s.kind.cluster"
+
+	// KindRoleLabel is the label Kind applies to every node container, with the node's role as its value.
+	KindRoleLabel = "io.x-k8s.kind.role"
+
+	// KindExternalLoadBalancerRole is the role of the container Kind runs in front of
+	// the API servers of clusters with multiple control plane nodes.
+	KindExternalLoadBalancerRole = "external-load-balancer"
+
 	// DefaultKindNetwork is the name of the default Docker network used for Kind clusters
 	DefaultKindNetwork = "kind"
 )
@@ -18,15 +35,50 @@ var (
 // Public Functions - Kind Helpers
 // -----------------------------------------------------------------------------
 
-// GetKindContainerID produces the docker container ID for the given kind cluster by name.
+// GetKindContainerID produces the docker container ID for the first control plane node
+// of the given kind cluster by name.
 func GetKindContainerID(clusterName string) string {
 	return fmt.Sprintf("%s%s", clusterName, KindContainerSuffix)
 }
 
+// GetKindNodeContainerIDs lists the docker container IDs (names) of every Kubernetes node
+// (control plane and worker) of the given kind cluster by name, in name order.
+func GetKindNodeContainerIDs(ctx context.Context, clusterName string) ([]string, error) {
+	dockerc, err := NewNegotiatedClientWithOpts(ctx, client.FromEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := dockerc.ContainerList(ctx, client.ContainerListOptions{
+		All:     true,
+		Filters: make(client.Filters).Add("label", fmt.Sprintf("%s=%s", KindClusterLabel, clusterName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	containerIDs := make([]string, 0, len(res.Items))
+	for _, container := range res.Items {
+		if len(container.Names) == 0 || container.Labels[KindRoleLabel] == KindExternalLoadBalancerRole {
+			continue
+		}
+		containerIDs = append(containerIDs, strings.TrimPrefix(container.Names[0], "/"))
+	}
+	if len(containerIDs) == 0 {
+		return nil, fmt.Errorf("no node containers found for kind cluster %s", clusterName)
+	}
+	sort.Strings(containerIDs)
+
+	return containerIDs, nil
+}
+
 // GetContainerIP retrieves the IPv4 address of a Kind container given the cluster name.
 func GetKindContainerIP(clusterName string) (string, error) {
-	containerID := GetKindContainerID(clusterName)
+	return GetKindNodeContainerIP(GetKindContainerID(clusterName))
+}
 
+// GetKindNodeContainerIP retrieves the IPv4 address of any Kind node container given its ID.
+func GetKindNodeContainerIP(containerID string) (string, error) {
 	res, err := InspectDockerContainer(containerID)
 	if err != nil {
 		return "", err
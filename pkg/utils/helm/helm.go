@@ -0,0 +1,365 @@
+// Package helm deploys chart releases to clusters with the Helm Go SDK, so
+// that neither the helm binary nor the local helm repositories are needed.
+package helm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// -----------------------------------------------------------------------------
+// Public Types
+// -----------------------------------------------------------------------------
+
+// Chart identifies a chart of a chart repository.
+type Chart struct {
+	// RepoURL is the URL of the chart repository.
+	RepoURL string
+
+	// Name is the name of the chart in the repository.
+	Name string
+
+	// Version is the version of the chart, the latest version is used if
+	// it's empty.
+	Version string
+
+	// Archive is the path of a local chart archive (or chart directory) which
+	// is used instead of the chart repository if it's set.
+	Archive string
+}
+
+// Options configures how a release is installed or upgraded.
+type Options struct {
+	// SkipCRDs skips the installation of the chart's CRDs.
+	SkipCRDs bool
+
+	// Wait waits until the release's resources are ready.
+	Wait bool
+
+	// Atomic uninstalls the release again if its install fails, instead of
+	// keeping the failed release for diagnosis. It implies Wait. It only
+	// applies to installs.
+	Atomic bool
+
+	// Timeout is the maximum amount of time to wait for the release's
+	// resources. If it's not set the context's deadline applies, or
+	// DefaultTimeout if the context has none.
+	Timeout time.Duration
+}
+
+// DefaultTimeout is the amount of time to wait for the resources of a release
+// to become ready when neither a timeout nor a context deadline is set.
+const DefaultTimeout = time.Minute * 5
+
+// ReleaseStatusError indicates that a release can't be installed or upgraded
+// because of its status, e.g. another operation is still pending.
+type ReleaseStatusError struct {
+	Name      string
+	Namespace string
+	Status    release.Status
+}
+
+func (e *ReleaseStatusError) Error() string {
+	return fmt.Sprintf("release %s in namespace %s is %s", e.Name, e.Namespace, e.Status)
+}
+
+// ErrReleaseNotFound indicates that there is no release with the given name.
+var ErrReleaseNotFound = driver.ErrReleaseNotFound
+
+// -----------------------------------------------------------------------------
+// Client
+// -----------------------------------------------------------------------------
+
+// Client manages the chart releases of a namespace.
+type Client struct {
+	namespace string
+	config    *action.Configuration
+	settings  *cli.EnvSettings
+}
+
+// NewClient provides a new Client for the releases of the namespace of the
+// cluster reachable with the given config. Helm's debug logs are sent to logf.
+func NewClient(config *rest.Config, namespace string, logf func(format string, v ...any)) (*Client, error) {
+	actionConfig := new(action.Configuration)
+	getter := &restClientGetter{config: config, namespace: namespace}
+	if err := actionConfig.Init(getter, namespace, "secret", logf); err != nil {
+		return nil, fmt.Errorf("failed to configure helm: %w", err)
+	}
+
+	return &Client{
+		namespace: namespace,
+		config:    actionConfig,
+		settings:  cli.New(),
+	}, nil
+}
+
+// Status provides the status of the latest revision of the release, or an
+// error wrapping ErrReleaseNotFound if there is none.
+func (c *Client) Status(name string) (release.Status, error) {
+	history, err := action.NewHistory(c.config).Run(name)
+	if err != nil {
+		return release.StatusUnknown, fmt.Errorf("failed to get the history of release %s: %w", name, err)
+	}
+	if len(history) == 0 {
+		return release.StatusUnknown, fmt.Errorf("release %s: %w", name, ErrReleaseNotFound)
+	}
+	releaseutil.Reverse(history, releaseutil.SortByRevision)
+	return history[0].Info.Status, nil
+}
+
+// Install installs the chart as a new release. If the install fails the
+// failed release is kept unless the install is Atomic. If the release exists
+// already a *ReleaseStatusError is returned.
+func (c *Client) Install(ctx context.Context, name string, ch Chart, values map[string]any, opts Options) error {
+	status, err := c.Status(name)
+	switch {
+	case err == nil:
+		return &ReleaseStatusError{Name: name, Namespace: c.namespace, Status: status}
+	case !errors.Is(err, ErrReleaseNotFound):
+		return err
+	}
+
+	loaded, err := c.loadChart(ch)
+	if err != nil {
+		return err
+	}
+
+	install := action.NewInstall(c.config)
+	install.ReleaseName = name
+	install.Namespace = c.namespace
+	install.Version = ch.Version
+	install.SkipCRDs = opts.SkipCRDs
+	install.Atomic = opts.Atomic
+	install.Wait = opts.Wait || opts.Atomic
+	install.Timeout = timeout(ctx, opts)
+	if _, err := install.RunWithContext(ctx, loaded, values); err != nil {
+		return fmt.Errorf("failed to install release %s: %w", name, err)
+	}
+	return nil
+}
+
+// Upgrade upgrades an existing release to the chart and values. If the release
+// doesn't exist an error wrapping ErrReleaseNotFound is returned, and if it's
+// pending another operation a *ReleaseStatusError is returned.
+func (c *Client) Upgrade(ctx context.Context, name string, ch Chart, values map[string]any, opts Options) error {
+	status, err := c.Status(name)
+	if err != nil {
+		return err
+	}
+	if status != release.StatusDeployed && status != release.StatusFailed {
+		return &ReleaseStatusError{Name: name, Namespace: c.namespace, Status: status}
+	}
+
+	loaded, err := c.loadChart(ch)
+	if err != nil {
+		return err
+	}
+
+	upgrade := action.NewUpgrade(c.config)
+	upgrade.Namespace = c.namespace
+	upgrade.Version = ch.Version
+	upgrade.SkipCRDs = opts.SkipCRDs
+	upgrade.Wait = opts.Wait
+	upgrade.Timeout = timeout(ctx, opts)
+	if _, err := upgrade.RunWithContext(ctx, name, loaded, values); err != nil {
+		return fmt.Errorf("failed to upgrade release %s: %w", name, err)
+	}
+	return nil
+}
+
+// InstallOrUpgrade installs the release if it doesn't exist yet and upgrades it
+// otherwise, like `helm upgrade --install`.
+func (c *Client) InstallOrUpgrade(ctx context.Context, name string, ch Chart, values map[string]any, opts Options) error {
+	_, err := c.Status(name)
+	if errors.Is(err, ErrReleaseNotFound) {
+		return c.Install(ctx, name, ch, values, opts)
+	}
+	return c.Upgrade(ctx, name, ch, values, opts)
+}
+
+// Uninstall uninstalls the release, or returns an error wrapping
+// ErrReleaseNotFound if it doesn't exist.
+func (c *Client) Uninstall(name string) error {
+	if _, err := action.NewUninstall(c.config).Run(name); err != nil {
+		return fmt.Errorf("failed to uninstall release %s: %w", name, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Templates
+// -----------------------------------------------------------------------------
+
+// Template renders the chart's manifests for a release with the given values,
+// like `helm template`, without using a cluster. The chart must be a local
+// Archive, as charts aren't downloaded.
+func Template(ctx context.Context, name, namespace string, ch Chart, values map[string]any) ([]byte, error) {
+	if ch.Archive == "" {
+		return nil, fmt.Errorf("can't render chart %s: no local chart archive", ch.Name)
+	}
+	loaded, err := loadArchive(ch.Archive)
+	if err != nil {
+		return nil, err
+	}
+
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...any) {}})
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	rel, err := install.RunWithContext(ctx, loaded, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", loaded.Name(), err)
+	}
+
+	manifests := bytes.NewBufferString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		fmt.Fprintf(manifests, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return manifests.Bytes(), nil
+}
+
+// -----------------------------------------------------------------------------
+// Values
+// -----------------------------------------------------------------------------
+
+// MergeValues merges the values into the base values like helm merges values
+// files: maps are merged recursively, and any other value (including lists)
+// replaces the base value. A nil value is kept, so that helm removes the key
+// from the chart's default values. Neither map is modified, and the merged
+// values share no maps or lists with them.
+func MergeValues(base, values map[string]any) map[string]any {
+	merged := copyValues(base)
+	for key, value := range values {
+		if valueMap, ok := value.(map[string]any); ok {
+			if baseMap, ok := merged[key].(map[string]any); ok {
+				merged[key] = MergeValues(baseMap, valueMap)
+				continue
+			}
+		}
+		merged[key] = copyValue(value)
+	}
+	return merged
+}
+
+// -----------------------------------------------------------------------------
+// Private Functions
+// -----------------------------------------------------------------------------
+
+// loadChart downloads the chart into helm's repository cache and loads it. The
+// local helm repositories aren't used or updated.
+func (c *Client) loadChart(ch Chart) (*chart.Chart, error) {
+	if ch.Archive != "" {
+		return loadArchive(ch.Archive)
+	}
+
+	pathOptions := action.ChartPathOptions{RepoURL: ch.RepoURL, Version: ch.Version}
+	path, err := pathOptions.LocateChart(ch.Name, c.settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download chart %s from %s: %w", ch.Name, ch.RepoURL, err)
+	}
+	loaded, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %w", ch.Name, err)
+	}
+	return loaded, nil
+}
+
+func loadArchive(path string) (*chart.Chart, error) {
+	loaded, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart archive %s: %w", path, err)
+	}
+	return loaded, nil
+}
+
+// timeout provides the amount of time to wait for the resources of a release:
+// the configured timeout, or else the time left until the context's deadline,
+// or else DefaultTimeout.
+func timeout(ctx context.Context, opts Options) time.Duration {
+	if opts.Timeout != 0 {
+		return opts.Timeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return DefaultTimeout
+}
+
+// restClientGetter provides the clients of the cluster reachable with a
+// *rest.Config to helm, which otherwise expects a kubeconfig.
+type restClientGetter struct {
+	config    *rest.Config
+	namespace string
+}
+
+func (g *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.CopyConfig(g.config), nil
+}
+
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(discoveryClient), nil
+}
+
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+	return restmapper.NewShortcutExpander(mapper, discoveryClient, nil), nil
+}
+
+// ToRawKubeConfigLoader is only used by helm for the default namespace.
+func (g *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return clientcmd.NewDefaultClientConfig(*clientcmdapi.NewConfig(), &clientcmd.ConfigOverrides{
+		Context: clientcmdapi.Context{Namespace: g.namespace},
+	})
+}
+
+func copyValues(values map[string]any) map[string]any {
+	copied := make(map[string]any, len(values))
+	for key, value := range values {
+		copied[key] = copyValue(value)
+	}
+	return copied
+}
+
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return copyValues(v)
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,133 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func newTestClient(t *testing.T, releases ...*release.Release) *Client {
+	store := storage.Init(driver.NewMemory())
+	for _, r := range releases {
+		require.NoError(t, store.Create(r))
+	}
+	return &Client{
+		namespace: "test",
+		config: &action.Configuration{
+			Releases:   store,
+			KubeClient: &kubefake.PrintingKubeClient{Out: io.Discard},
+			Log:        t.Logf,
+		},
+	}
+}
+
+func newRelease(name string, version int, status release.Status) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "test",
+		Version:   version,
+		Info:      &release.Info{Status: status},
+	}
+}
+
+func TestStatus(t *testing.T) {
+	client := newTestClient(t,
+		newRelease("kong", 1, release.StatusSuperseded),
+		newRelease("kong", 2, release.StatusDeployed),
+	)
+
+	status, err := client.Status("kong")
+	require.NoError(t, err)
+	assert.Equal(t, release.StatusDeployed, status, "the status of the latest revision is provided")
+
+	_, err = client.Status("kuma")
+	require.ErrorIs(t, err, ErrReleaseNotFound)
+}
+
+func TestReleaseStatusErrors(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t,
+		newRelease("deployed", 1, release.StatusDeployed),
+		newRelease("pending", 1, release.StatusPendingInstall),
+	)
+	var statusErr *ReleaseStatusError
+
+	t.Log("verifying that existing releases aren't installed again")
+	require.ErrorAs(t, client.Install(ctx, "deployed", Chart{}, nil, Options{}), &statusErr)
+	assert.Equal(t, release.StatusDeployed, statusErr.Status)
+
+	t.Log("verifying that pending releases aren't upgraded")
+	require.ErrorAs(t, client.Upgrade(ctx, "pending", Chart{}, nil, Options{}), &statusErr)
+	assert.Equal(t, release.StatusPendingInstall, statusErr.Status)
+	require.ErrorAs(t, client.InstallOrUpgrade(ctx, "pending", Chart{}, nil, Options{}), &statusErr)
+
+	t.Log("verifying that missing releases can't be upgraded")
+	require.ErrorIs(t, client.Upgrade(ctx, "missing", Chart{}, nil, Options{}), ErrReleaseNotFound)
+}
+
+func TestInstallFailures(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: test\nversion: 0.1.0\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "hook.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n  annotations:\n    helm.sh/hook: pre-install\n"), 0o600))
+	ch := Chart{Archive: dir}
+
+	client := newTestClient(t)
+	client.config.Capabilities = chartutil.DefaultCapabilities
+	client.config.KubeClient = &kubefake.FailingKubeClient{
+		PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard},
+		CreateError:        errors.New("create failed"),
+	}
+
+	t.Log("verifying that failed releases are kept by default")
+	require.ErrorContains(t, client.Install(ctx, "kept", ch, nil, Options{}), "create failed")
+	status, err := client.Status("kept")
+	require.NoError(t, err)
+	assert.Equal(t, release.StatusFailed, status)
+
+	t.Log("verifying that failed atomic releases are uninstalled")
+	require.ErrorContains(t, client.Install(ctx, "atomic", ch, nil, Options{Atomic: true}), "create failed")
+	_, err = client.Status("atomic")
+	require.ErrorIs(t, err, ErrReleaseNotFound)
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]any{
+		"image": map[string]any{"repository": "kong", "tag": "3.4"},
+		"env":   map[string]any{"database": "off"},
+		"ports": []any{80, 443},
+	}
+	values := map[string]any{
+		"image":       map[string]any{"tag": "3.9"},
+		"env":         "unset",
+		"ports":       []any{8000},
+		"annotations": map[string]any{"konghq.com/protocol": "http"},
+	}
+
+	merged := MergeValues(base, values)
+	assert.Equal(t, map[string]any{
+		"image":       map[string]any{"repository": "kong", "tag": "3.9"},
+		"env":         "unset",
+		"ports":       []any{8000},
+		"annotations": map[string]any{"konghq.com/protocol": "http"},
+	}, merged)
+
+	t.Log("verifying that the merged values don't share maps with the inputs")
+	merged["image"].(map[string]any)["tag"] = "latest"
+	merged["annotations"].(map[string]any)["other"] = "value"
+	assert.Equal(t, "3.4", base["image"].(map[string]any)["tag"])
+	assert.Len(t, values["annotations"], 1)
+}
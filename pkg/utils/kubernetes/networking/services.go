@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/netip"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,40 +16,103 @@ import (
 
 // WaitForServiceLoadBalancerAddress waits for a service provided by
 // namespace/name to have an ingress IP or Host provisioned and returns that
-// address. This function will throw an error if the service gets provisioned
-// more than a single address, that is not supported. The context provided
-// should have a timeout associated with it or you're going to have a bad time.
+// address. Dual-stack services get one address per IP family, in which case
+// the address of the service's primary IP family is returned. This function
+// will throw an error if the service gets provisioned more than a single
+// address per family, that is not supported. The context provided should have
+// a timeout associated with it or you're going to have a bad time.
 func WaitForServiceLoadBalancerAddress(ctx context.Context, c kubernetes.Interface, namespace, name string) (string, bool, error) {
+	addresses, isIP, err := WaitForServiceLoadBalancerAddresses(ctx, c, namespace, name)
+	if err != nil {
+		return "", false, err
+	}
+	return addresses[0], isIP, nil
+}
+
+// WaitForServiceLoadBalancerAddresses waits for a service provided by
+// namespace/name to have an ingress Host or an ingress IP for each of its IP
+// families provisioned and returns those addresses, ordered like the IP families
+// of the service (primary family first). The context provided should have a
+// timeout associated with it or you're going to have a bad time.
+func WaitForServiceLoadBalancerAddresses(ctx context.Context, c kubernetes.Interface, namespace, name string) ([]string, bool, error) {
 	for {
 		select {
 		case <-ctx.Done():
-			return "", false, fmt.Errorf("context completed while waiting for loadbalancer service to provision: %w", ctx.Err())
+			return nil, false, fmt.Errorf("context completed while waiting for loadbalancer service to provision: %w", ctx.Err())
 		default:
 			// retrieve a fresh copy of the service
 			service, err := c.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 			if err != nil {
-				return "", false, fmt.Errorf("error while trying to retrieve registry service: %w", err)
+				return nil, false, fmt.Errorf("error while trying to retrieve registry service: %w", err)
 			}
-			lbing := service.Status.LoadBalancer.Ingress
 
-			// don't support services which have multiple addresses
+			addresses, isIP, err := loadBalancerAddresses(service)
+			if err != nil {
+				return nil, false, err
+			}
+			if len(addresses) > 0 {
+				return addresses, isIP, nil
+			}
+		}
+	}
+}
+
+// loadBalancerAddresses provides the load balancer addresses of a service once
+// they are fully provisioned, or no addresses if provisioning is still ongoing.
+func loadBalancerAddresses(service *corev1.Service) ([]string, bool, error) {
+	lbing := service.Status.LoadBalancer.Ingress
+
+	// hostnames can resolve to addresses of any family, so only one is supported
+	for _, ing := range lbing {
+		if ing.Hostname != "" {
 			if len(lbing) > 1 {
-				return "", false, fmt.Errorf("services with more than one load balancer address are not supported (found %d)", len(lbing))
+				return nil, false, fmt.Errorf("services with more than one load balancer address are not supported when one is a hostname (found %d)", len(lbing))
 			}
+			return []string{ing.Hostname}, false, nil
+		}
+	}
 
-			// verify whether the loadbalancer details are provisioned
-			if len(lbing) == 1 {
-				for _, ing := range lbing {
-					if ing.Hostname != "" {
-						return ing.Hostname, false, nil
-					}
-					if ing.IP != "" {
-						return ing.IP, true, nil
-					}
-				}
+	ipsByFamily := make(map[corev1.IPFamily]string, len(lbing))
+	for _, ing := range lbing {
+		if ing.IP == "" {
+			continue
+		}
+		ip, err := netip.ParseAddr(ing.IP)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid load balancer address %q: %w", ing.IP, err)
+		}
+		family := corev1.IPv4Protocol
+		if ip.Unmap().Is6() {
+			family = corev1.IPv6Protocol
+		}
+		// don't support services which have multiple addresses of the same family
+		if _, ok := ipsByFamily[family]; ok {
+			return nil, false, fmt.Errorf("services with more than one load balancer address per IP family are not supported (found %d)", len(lbing))
+		}
+		ipsByFamily[family] = ing.IP
+	}
+
+	families := service.Spec.IPFamilies
+	if len(families) == 0 {
+		families = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	}
+	addresses := make([]string, 0, len(families))
+	for _, family := range families {
+		ip, ok := ipsByFamily[family]
+		if !ok {
+			// an address for this family has yet to be provisioned
+			if len(service.Spec.IPFamilies) > 0 {
+				return nil, false, nil
 			}
+			continue
 		}
+		addresses = append(addresses, ip)
+	}
+	if len(addresses) == 0 {
+		return nil, false, nil
 	}
+
+	return addresses, true, nil
 }
 
 // WaitForConnectionOnServicePort waits until it can make successful TCP connections
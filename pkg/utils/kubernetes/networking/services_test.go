@@ -0,0 +1,97 @@
+package networking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLoadBalancerAddresses(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		families  []corev1.IPFamily
+		ingress   []corev1.LoadBalancerIngress
+		addresses []string
+		isIP      bool
+		wantErr   bool
+	}{
+		{
+			name: "not provisioned yet",
+		},
+		{
+			name:      "single IPv4 address",
+			families:  []corev1.IPFamily{corev1.IPv4Protocol},
+			ingress:   []corev1.LoadBalancerIngress{{IP: "172.18.0.100"}},
+			addresses: []string{"172.18.0.100"},
+			isIP:      true,
+		},
+		{
+			name:      "hostname",
+			ingress:   []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			addresses: []string{"lb.example.com"},
+		},
+		{
+			name:      "dual-stack ordered by service IP families",
+			families:  []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			ingress:   []corev1.LoadBalancerIngress{{IP: "172.18.0.100"}, {IP: "fc00:f853:ccd:e793::64"}},
+			addresses: []string{"fc00:f853:ccd:e793::64", "172.18.0.100"},
+			isIP:      true,
+		},
+		{
+			name:     "dual-stack waits for every family",
+			families: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			ingress:  []corev1.LoadBalancerIngress{{IP: "172.18.0.100"}},
+		},
+		{
+			name:     "multiple addresses of one family",
+			families: []corev1.IPFamily{corev1.IPv4Protocol},
+			ingress:  []corev1.LoadBalancerIngress{{IP: "172.18.0.100"}, {IP: "172.18.0.101"}},
+			wantErr:  true,
+		},
+		{
+			name:    "hostname and address",
+			ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}, {IP: "172.18.0.100"}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &corev1.Service{
+				Spec:   corev1.ServiceSpec{IPFamilies: tt.families},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: tt.ingress}},
+			}
+			addresses, isIP, err := loadBalancerAddresses(service)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.addresses, addresses)
+			assert.Equal(t, tt.isIP, isIP)
+		})
+	}
+}
+
+func TestWaitForServiceLoadBalancerAddressDualStack(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	c := fake.NewClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "proxy"},
+		Spec:       corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{
+			{IP: "fc00:f853:ccd:e793::64"},
+			{IP: "172.18.0.100"},
+		}}},
+	})
+
+	address, isIP, err := WaitForServiceLoadBalancerAddress(ctx, c, "default", "proxy")
+	require.NoError(t, err)
+	assert.True(t, isIP)
+	assert.Equal(t, "172.18.0.100", address)
+}
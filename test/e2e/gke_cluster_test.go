@@ -83,7 +83,7 @@ func testGKECluster(t *testing.T, createSubnet bool) {
 	})
 
 	t.Log("verifying that the cluster can be communicated with")
-	version, err := cluster.Client().ServerVersion()
+	version, err := cluster.Client().Discovery().ServerVersion()
 	require.NoError(t, err)
 	t.Logf("server version found: %s", version)
 
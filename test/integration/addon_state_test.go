@@ -0,0 +1,51 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestAddonStateRehydration(t *testing.T) {
+	t.Parallel()
+
+	t.Log("building the testing environment and Kubernetes cluster with addons")
+	env, err := environment.NewBuilder().WithAddons(metallb.New(), kong.NewBuilder().WithNamespace("kong-state").Build()).Build(ctx)
+	require.NoError(t, err)
+
+	t.Logf("setting up the environment cleanup for environment %s and cluster %s", env.Name(), env.Cluster().Name())
+	t.Cleanup(func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		require.NoError(t, env.Cleanup(ctx))
+	})
+
+	t.Log("waiting for the test environment to be ready for use")
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("attaching to the existing cluster as if from another process")
+	cluster, err := kind.NewFromExisting(env.Cluster().Name())
+	require.NoError(t, err)
+
+	t.Log("verifying that the addons were rehydrated from the cluster")
+	require.Len(t, cluster.ListAddons(), 2)
+	addon, err := cluster.GetAddon(kong.AddonName)
+	require.NoError(t, err)
+	kongAddon, ok := addon.(*kong.Addon)
+	require.True(t, ok)
+	require.Equal(t, "kong-state", kongAddon.Namespace())
+
+	t.Log("verifying that the rehydrated kong addon can be used")
+	_, err = kongAddon.ProxyHTTPURL(ctx, cluster)
+	require.NoError(t, err)
+	waitForObjects, ready, err := kongAddon.Ready(ctx, cluster)
+	require.NoError(t, err)
+	require.Empty(t, waitForObjects)
+	require.True(t, ready)
+}
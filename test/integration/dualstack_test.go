@@ -0,0 +1,61 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/environments"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/kubernetes/networking"
+)
+
+func TestKindClusterWithDualStack(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the test environment with dual-stack networking enabled")
+	builder := environments.NewBuilder().WithDualStack().WithAddons(metallb.New(), kong.New())
+
+	t.Log("building the testing environment and Kubernetes cluster")
+	env, err := builder.Build(ctx)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, env.Cleanup(ctx)) }()
+	require.Equal(t, clusters.Dual, env.Cluster().IPFamily())
+
+	t.Log("waiting for the testing environment to be ready")
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("waiting for the proxy to get a load balancer address for each IP family")
+	addresses, isIP, err := networking.WaitForServiceLoadBalancerAddresses(ctx, env.Cluster().Client(), kong.DefaultNamespace, kong.DefaultProxyServiceName)
+	require.NoError(t, err)
+	require.True(t, isIP)
+	require.Len(t, addresses, 2)
+
+	var families []bool
+	for _, address := range addresses {
+		ip := netip.MustParseAddr(address)
+		families = append(families, ip.Is4())
+
+		t.Logf("verifying the proxy is reachable on %s", address)
+		proxyURL := url.URL{Scheme: "http", Host: netip.AddrPortFrom(ip, 80).String()}
+		require.Eventually(t, func() bool {
+			resp, err := http.Get(proxyURL.String())
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			// we don't care that the proxy has nothing to serve so long as we can talk to it and get a valid HTTP response
+			return resp.StatusCode == http.StatusNotFound
+		}, time.Minute*3, time.Second)
+	}
+	require.ElementsMatch(t, []bool{true, false}, families)
+}
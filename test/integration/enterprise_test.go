@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	gokong "github.com/kong/go-kong/kong"
 	"github.com/sethvargo/go-password/password"
 	"github.com/stretchr/testify/require"
 
@@ -57,6 +58,42 @@ func TestKongEnterpriseDBLess(t *testing.T) {
 	deployAndTestKongEnterprise(t, kongAddon, "")
 }
 
+func TestKongEnterpriseRBAC(t *testing.T) {
+	SkipEnterpriseTestIfNoEnv(t)
+
+	licenseJSON := prepareKongEnterpriseLicense(t)
+
+	t.Skip("skipping as enterprise tests fail to read the license: https://github.com/Kong/kubernetes-testing-framework/issues/1518")
+
+	t.Log("configuring the testing environment with a read-only RBAC user in a workspace")
+	kongAddon := kongaddon.NewBuilder().
+		WithProxyAdminServiceTypeLoadBalancer().
+		WithPostgreSQL().
+		WithProxyEnterpriseEnabled(licenseJSON).
+		WithRBACRole(kongaddon.RBACRole{
+			Name:        "reader",
+			Workspace:   "team-a",
+			Permissions: []kongaddon.RBACEndpointPermission{{Endpoint: "*", Actions: []string{"read"}}},
+		}).
+		WithRBACUser(kongaddon.RBACUser{Name: "alice", Workspace: "team-a", Roles: []string{"reader"}}).
+		Build()
+	env, err := environment.NewBuilder().WithAddons(kongAddon, metallbaddon.New()).Build(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		require.NoError(t, env.Cleanup(ctx))
+	})
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("verifying that the RBAC user can read but not create entities in its workspace")
+	client, err := kongAddon.AdminClientAs(ctx, env.Cluster(), "alice")
+	require.NoError(t, err)
+	_, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	_, err = client.Services.Create(ctx, &gokong.Service{Name: gokong.String("httpbin"), Host: gokong.String("httpbin.default.svc")})
+	require.True(t, gokong.IsForbiddenErr(err), err)
+}
+
 // deployAndTestKongEnterprise deploys a Kong Enterprise cluster and tests it for basic functionality.
 // It works for both DB-less and DB-mode deployments (configuration of kongAddon). For DB-less set adminPassword to "".
 // It verifies that workspace (enterprise feature) can be successfully created.
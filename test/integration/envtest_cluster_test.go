@@ -0,0 +1,76 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/registry"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/envtest"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/kubernetes/generators"
+)
+
+func TestEnvTestCluster(t *testing.T) {
+	t.Parallel()
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.ktf.konghq.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "ktf.konghq.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "widgets",
+				Singular: "widget",
+				Kind:     "Widget",
+				ListKind: "WidgetList",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"},
+				},
+			}},
+		},
+	}
+
+	t.Log("starting an envtest cluster with a CRD installed")
+	cluster, err := envtest.NewBuilder().WithCRDs(crd).Build(ctx)
+	require.NoError(t, err)
+	defer func() {
+		t.Logf("cleaning up envtest cluster %s", cluster.Name())
+		assert.NoError(t, cluster.Cleanup(ctx))
+	}()
+	require.Equal(t, envtest.EnvTestClusterType, cluster.Type())
+
+	t.Log("verifying the cluster version can be detected")
+	_, err = cluster.Version()
+	require.NoError(t, err)
+
+	t.Log("verifying the CRD was installed")
+	resources, err := cluster.Client().Discovery().ServerResourcesForGroupVersion(schema.GroupVersion{Group: "ktf.konghq.com", Version: "v1"}.String())
+	require.NoError(t, err)
+	require.Len(t, resources.APIResources, 1)
+	require.Equal(t, "widgets", resources.APIResources[0].Name)
+
+	t.Log("verifying that API objects can be created")
+	deployment := generators.NewDeploymentForContainer(generators.NewContainer("httpbin", "kennethreitz/httpbin", 80))
+	_, err = cluster.Client().AppsV1().Deployments("default").Create(ctx, deployment, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Log("verifying that addons which need workloads are rejected")
+	for _, addon := range []clusters.Addon{kong.New(), metallb.New(), registry.New()} {
+		err = cluster.DeployAddon(ctx, addon)
+		require.ErrorIs(t, err, clusters.ErrUnsupportedClusterType, "addon %s", addon.Name())
+	}
+}
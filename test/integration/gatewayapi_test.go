@@ -0,0 +1,102 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/gatewayapi"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/httpbin"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestGatewayAPIAddon(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the testing environment with the gateway API and kong as its implementation")
+	gatewayAPIAddon := gatewayapi.NewBuilder().WithChannel(gatewayapi.ExperimentalChannel).Build()
+	kongAddon := kong.NewBuilder().WithGatewayAPI("").Build()
+	httpbinAddon := httpbin.New()
+	env, err := environment.NewBuilder().WithAddons(metallb.New(), gatewayAPIAddon, kongAddon, httpbinAddon).Build(ctx)
+	require.NoError(t, err)
+	defer func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		assert.NoError(t, env.Cleanup(ctx))
+	}()
+	require.NoError(t, <-env.WaitForReady(ctx))
+	require.NotNil(t, gatewayAPIAddon.Version())
+
+	client, err := gatewayclient.NewForConfig(env.Cluster().Config())
+	require.NoError(t, err)
+
+	t.Log("verifying that the ingress controller accepts the GatewayClass")
+	require.Eventually(t, func() bool {
+		gatewayClass, err := client.GatewayV1().GatewayClasses().Get(ctx, kongAddon.GatewayClassName(), metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		return meta.IsStatusConditionTrue(gatewayClass.Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted))
+	}, time.Minute, time.Second)
+
+	t.Log("routing requests to httpbin through a Gateway and an HTTPRoute")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: gatewayv1.ObjectName(kongAddon.GatewayClassName()),
+			Listeners: []gatewayv1.Listener{{
+				Name:     "http",
+				Protocol: gatewayv1.HTTPProtocolType,
+				Port:     gatewayv1.PortNumber(kong.DefaultProxyHTTPPort),
+			}},
+		},
+	}
+	_, err = client.GatewayV1().Gateways(httpbinAddon.Namespace()).Create(ctx, gateway, metav1.CreateOptions{})
+	require.NoError(t, err)
+	pathPrefix := gatewayv1.PathMatchPathPrefix
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "httpbin"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway.Name)}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{Type: &pathPrefix, Value: &[]string{"/httpbin"}[0]},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: gatewayv1.ObjectName(httpbin.AddonName),
+							Port: &[]gatewayv1.PortNumber{httpbin.DefaultPort}[0],
+						},
+					},
+				}},
+			}},
+		},
+	}
+	_, err = client.GatewayV1().HTTPRoutes(httpbinAddon.Namespace()).Create(ctx, route, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Log("verifying that httpbin can be reached through the kong proxy")
+	proxyURL, err := kongAddon.ProxyHTTPURL(ctx, env.Cluster())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(proxyURL.String() + "/httpbin/status/200")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, time.Minute*2, time.Second)
+}
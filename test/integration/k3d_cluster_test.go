@@ -0,0 +1,64 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/k3d"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestK3dClusterBasics(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the testing environment with a k3d cluster")
+	builder := environment.NewBuilder().WithClusterBuilder(k3d.NewBuilder())
+
+	t.Log("building the testing environment and Kubernetes cluster")
+	env, err := builder.WithAddons(metallb.New(), kong.New()).Build(ctx)
+	require.NoError(t, err)
+
+	t.Logf("setting up the environment cleanup for environment %s and cluster %s", env.Name(), env.Cluster().Name())
+	defer func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		require.NoError(t, env.Cleanup(ctx))
+	}()
+
+	t.Log("verifying the cluster type")
+	require.Equal(t, k3d.K3dClusterType, env.Cluster().Type())
+
+	t.Log("waiting for the test environment to be ready for use")
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Logf("pulling the kong addon from the environment's cluster to verify proxy URL")
+	kongAddon, err := env.Cluster().GetAddon("kong")
+	require.NoError(t, err)
+	kongAddonRaw, ok := kongAddon.(*kong.Addon)
+	require.True(t, ok)
+	proxyURL, err := kongAddonRaw.ProxyHTTPURL(ctx, env.Cluster())
+	require.NoError(t, err)
+
+	t.Log("verifying the kong proxy is returning its default 404 response")
+	httpc := http.Client{Timeout: time.Second * 10}
+	require.Eventually(t, func() bool {
+		resp, err := httpc.Get(proxyURL.String())
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusNotFound
+	}, time.Minute*3, time.Second)
+
+	t.Log("verifying that an existing k3d cluster can be attached to by name")
+	existing, err := k3d.NewFromExisting(env.Cluster().Name())
+	require.NoError(t, err)
+	_, err = existing.Version()
+	require.NoError(t, err)
+}
@@ -0,0 +1,59 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/docker"
+)
+
+func TestKindClusterMultiNode(t *testing.T) {
+	t.Parallel()
+
+	taint := corev1.Taint{Key: "ktf.konghq.com/dedicated", Value: "proxy", Effect: corev1.TaintEffectNoSchedule}
+
+	t.Log("building a kind cluster with one control plane node and two worker nodes")
+	cluster, err := kind.NewBuilder().
+		WithWorkerNodes(2).
+		WithNodeLabels(v1alpha4.WorkerRole, 0, map[string]string{"ktf.konghq.com/zone": "a"}).
+		WithNodeLabels(v1alpha4.WorkerRole, 1, map[string]string{"ktf.konghq.com/zone": "b"}).
+		WithNodeTaints(v1alpha4.WorkerRole, 1, taint).
+		Build(ctx)
+	require.NoError(t, err)
+	defer func() {
+		t.Logf("cleaning up cluster %s", cluster.Name())
+		assert.NoError(t, cluster.Cleanup(ctx))
+	}()
+
+	t.Log("verifying that every node has a container")
+	containerIDs, err := docker.GetKindNodeContainerIDs(ctx, cluster.Name())
+	require.NoError(t, err)
+	require.Len(t, containerIDs, 3)
+	for _, containerID := range containerIDs {
+		_, err := docker.GetKindNodeContainerIP(containerID)
+		require.NoError(t, err)
+	}
+
+	t.Log("verifying that the worker nodes are labeled and tainted")
+	nodes, err := cluster.Client().CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "ktf.konghq.com/zone"})
+	require.NoError(t, err)
+	require.Len(t, nodes.Items, 2)
+	tainted := 0
+	for _, node := range nodes.Items {
+		for _, nodeTaint := range node.Spec.Taints {
+			if nodeTaint.MatchTaint(&taint) {
+				tainted++
+				assert.Equal(t, "b", node.Labels["ktf.konghq.com/zone"])
+			}
+		}
+	}
+	require.Equal(t, 1, tainted)
+}
@@ -32,7 +32,7 @@ func TestKindWithClusterVersion(t *testing.T) {
 
 	t.Log("waiting for cluster api to become usable")
 	require.Eventually(t, func() bool {
-		_, err := cluster.Client().ServerVersion()
+		_, err := cluster.Client().Discovery().ServerVersion()
 		return err == nil
 	}, time.Minute, time.Second)
 
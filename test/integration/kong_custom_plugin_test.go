@@ -0,0 +1,61 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestKongAddonCustomPlugin(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the testing environment with a custom plugin loaded into kong")
+	kongAddon := kong.NewBuilder().
+		WithControllerDisabled().
+		WithCustomPlugin("my-header", filepath.Join("testdata", "plugins", "my-header")).
+		// the Admin API is used from the test runner
+		WithProxyAdminServiceTypeLoadBalancer().
+		Build()
+	env, err := environment.NewBuilder().WithAddons(metallb.New(), kongAddon).Build(ctx)
+	require.NoError(t, err)
+	defer func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		assert.NoError(t, env.Cleanup(ctx))
+	}()
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("enabling the custom plugin on a route to the proxy's own status endpoint")
+	require.NoError(t, kongAddon.SyncDeclarativeConfig(ctx, env.Cluster(), []byte(`_format_version: "3.0"
+services:
+- name: status
+  url: http://127.0.0.1:8100/status
+  routes:
+  - name: status
+    paths:
+    - /custom-plugin
+plugins:
+- name: my-header
+`)))
+
+	t.Log("verifying that the custom plugin sets its response header")
+	proxyURL, err := kongAddon.ProxyHTTPURL(ctx, env.Cluster())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(proxyURL.String() + "/custom-plugin")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK && resp.Header.Get("X-My-Header") == "loaded"
+	}, time.Minute, time.Second)
+}
@@ -0,0 +1,94 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+const declarativeConfig = `_format_version: "3.0"
+services:
+- name: httpbin
+  url: http://httpbin.default.svc
+  routes:
+  - name: httpbin
+    paths:
+    - /httpbin
+`
+
+func TestKongAddonDeclarativeConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		addon *kong.Addon
+	}{
+		// the controller would replace the configuration in DB-less mode, and
+		// the Admin API is used from the test runner.
+		{name: "dbless", addon: kong.NewBuilder().WithDBLess().WithControllerDisabled().WithProxyAdminServiceTypeLoadBalancer().Build()},
+		{name: "postgres", addon: kong.NewBuilder().WithPostgreSQL().WithControllerDisabled().WithProxyAdminServiceTypeLoadBalancer().Build()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Log("configuring the testing environment")
+			env, err := environment.NewBuilder().WithAddons(metallb.New(), tc.addon).Build(ctx)
+			require.NoError(t, err)
+			defer func() {
+				t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+				assert.NoError(t, env.Cleanup(ctx))
+			}()
+			require.NoError(t, <-env.WaitForReady(ctx))
+
+			t.Log("verifying that the gateway differs from the declarative config before it's synced")
+			diff, err := tc.addon.DiffDeclarativeConfig(ctx, env.Cluster(), []byte(declarativeConfig))
+			require.NoError(t, err)
+			require.Len(t, diff.Creating, 2, diff.String())
+
+			t.Log("syncing the declarative config and verifying that the gateway matches it")
+			require.NoError(t, tc.addon.SyncDeclarativeConfig(ctx, env.Cluster(), []byte(declarativeConfig)))
+			diff, err = tc.addon.DiffDeclarativeConfig(ctx, env.Cluster(), []byte(declarativeConfig))
+			require.NoError(t, err)
+			require.True(t, diff.Empty(), diff.String())
+
+			t.Log("verifying that entities missing from the expected config are reported")
+			diff, err = tc.addon.DiffDeclarativeConfig(ctx, env.Cluster(), []byte(`_format_version: "3.0"`))
+			require.NoError(t, err)
+			require.Len(t, diff.Deleting, 2, diff.String())
+		})
+	}
+}
+
+func TestKongAddonWaitForConfigSync(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the testing environment with kong in hybrid mode with 2 data planes")
+	// the control plane's Admin API is used from the test runner
+	kongAddon := kong.NewBuilder().WithHybridMode(2).WithProxyAdminServiceTypeLoadBalancer().Build()
+	env, err := environment.NewBuilder().WithAddons(metallb.New(), kongAddon).Build(ctx)
+	require.NoError(t, err)
+	defer func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		assert.NoError(t, env.Cleanup(ctx))
+	}()
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("syncing a declarative config and waiting for all the data planes to load it")
+	require.NoError(t, kongAddon.SyncDeclarativeConfig(ctx, env.Cluster(), []byte(declarativeConfig)))
+	waitCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	require.NoError(t, kongAddon.WaitForConfigSync(waitCtx, env.Cluster(), kong.ConfigSyncOptions{}))
+
+	t.Log("verifying that an unexpected hash isn't considered synced")
+	waitCtx, cancel = context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	err = kongAddon.WaitForConfigSync(waitCtx, env.Cluster(), kong.ConfigSyncOptions{ExpectedHash: "0123456789abcdef0123456789abcdef"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,69 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/httpbin"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/networking"
+)
+
+func TestKongAddonHybridMode(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the testing environment with kong in hybrid mode with 2 data planes")
+	// the control plane's Admin API is queried from the test runner
+	kongAddon := kong.NewBuilder().WithHybridMode(2).WithProxyAdminServiceTypeLoadBalancer().Build()
+	httpbinAddon := httpbin.New()
+	env, err := environment.NewBuilder().WithAddons(metallb.New(), kongAddon, httpbinAddon).Build(ctx)
+	require.NoError(t, err)
+
+	t.Logf("setting up the environment cleanup for environment %s and cluster %s", env.Name(), env.Cluster().Name())
+	defer func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		assert.NoError(t, env.Cleanup(ctx))
+	}()
+
+	t.Log("waiting for the test environment to be ready for use")
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("verifying that both data planes are connected to the control plane")
+	adminURL, err := kongAddon.ControlPlaneAdminURL(ctx, env.Cluster())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(adminURL.String() + "/clustering/data-planes")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		var dataPlanes struct {
+			Data []any `json:"data"`
+		}
+		return json.NewDecoder(resp.Body).Decode(&dataPlanes) == nil && len(dataPlanes.Data) == 2
+	}, time.Minute*2, time.Second)
+
+	t.Log("verifying that each data plane proxies traffic to httpbin")
+	proxyURLs, err := kongAddon.DataPlaneProxyURLs(ctx, env.Cluster())
+	require.NoError(t, err)
+	require.Len(t, proxyURLs, 2)
+	for _, proxyURL := range proxyURLs {
+		httpbinURL := fmt.Sprintf("%s/%s/status/418", proxyURL.String(), httpbinAddon.Path())
+		require.NoError(t, <-networking.WaitForHTTP(ctx, httpbinURL, http.StatusTeapot))
+	}
+
+	t.Log("verifying that the data planes are reported in the diagnostics")
+	diagnostics, err := kongAddon.DumpDiagnostics(ctx, env.Cluster())
+	require.NoError(t, err)
+	assert.Contains(t, diagnostics, "clustering_data_planes.json")
+}
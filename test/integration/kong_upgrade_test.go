@@ -0,0 +1,97 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/httpbin"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/kong"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/metallb"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+	"github.com/kong/kubernetes-testing-framework/pkg/utils/networking"
+)
+
+func TestKongAddonUpgrade(t *testing.T) {
+	t.Parallel()
+
+	t.Log("configuring the testing environment with kong gateway 3.4")
+	kongAddon := kong.NewBuilder().WithProxyImage("kong", "3.4").Build()
+	httpbinAddon := httpbin.New()
+	env, err := environment.NewBuilder().WithAddons(metallb.New(), kongAddon, httpbinAddon).Build(ctx)
+	require.NoError(t, err)
+
+	t.Logf("setting up the environment cleanup for environment %s and cluster %s", env.Name(), env.Cluster().Name())
+	defer func() {
+		t.Logf("cleaning up environment %s and cluster %s", env.Name(), env.Cluster().Name())
+		assert.NoError(t, env.Cleanup(ctx))
+	}()
+
+	t.Log("waiting for the test environment to be ready for use")
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("verifying that traffic is proxied to httpbin")
+	proxyURL, err := kongAddon.ProxyHTTPURL(ctx, env.Cluster())
+	require.NoError(t, err)
+	httpbinURL := fmt.Sprintf("%s/%s/status/418", proxyURL.String(), httpbinAddon.Path())
+	require.NoError(t, <-networking.WaitForHTTP(ctx, httpbinURL, http.StatusTeapot))
+
+	t.Log("sending traffic through the proxy continuously during the upgrade")
+	var sent, failed atomic.Int64
+	trafficCtx, stopTraffic := context.WithCancel(ctx)
+	trafficDone := make(chan struct{})
+	go func() {
+		defer close(trafficDone)
+		httpc := http.Client{Timeout: time.Second * 5}
+		for trafficCtx.Err() == nil {
+			sent.Add(1)
+			resp, err := httpc.Get(httpbinURL)
+			if err != nil {
+				failed.Add(1)
+				continue
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusTeapot {
+				failed.Add(1)
+			}
+			time.Sleep(time.Millisecond * 100)
+		}
+	}()
+
+	t.Log("upgrading kong gateway to 3.9")
+	upgradeCtx, cancel := context.WithTimeout(ctx, time.Minute*10)
+	defer cancel()
+	target := kong.NewBuilder().WithProxyImage("kong", "3.9").Build()
+	deployed, err := env.Cluster().GetAddon(kong.AddonName)
+	require.NoError(t, err)
+	err = clusters.UpgradeAddon(upgradeCtx, env.Cluster(), deployed, target)
+	stopTraffic()
+	<-trafficDone
+	require.NoError(t, err)
+
+	t.Log("verifying that the proxy runs the new image")
+	deployment, err := env.Cluster().Client().AppsV1().Deployments(kongAddon.Namespace()).Get(ctx, "ingress-controller-kong", metav1.GetOptions{})
+	require.NoError(t, err)
+	var proxyImage string
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "proxy" {
+			proxyImage = container.Image
+		}
+	}
+	require.Equal(t, "kong:3.9", proxyImage)
+
+	t.Log("verifying that no traffic was dropped during the upgrade")
+	require.Positive(t, sent.Load())
+	require.Zero(t, failed.Load(), "%d of %d requests failed during the upgrade", failed.Load(), sent.Load())
+	require.NoError(t, <-networking.WaitForHTTP(ctx, httpbinURL, http.StatusTeapot))
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	gokong "github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -185,6 +186,18 @@ func TestKongAddonDiagnostics(t *testing.T) {
 	errChan := env.WaitForReady(ctx)
 	require.NoError(t, <-errChan)
 
+	t.Log("configuring a service through the admin API client")
+	adminClient, err := kong.AdminClient(ctx, cluster, "")
+	require.NoError(t, err)
+	_, err = adminClient.Services.Create(ctx, &gokong.Service{
+		Name: gokong.String("diagnostics-test"),
+		URL:  gokong.String("http://httpbin.default.svc"),
+	})
+	require.NoError(t, err)
+	cachedClient, err := kong.AdminClient(ctx, cluster, "default")
+	require.NoError(t, err)
+	require.Same(t, adminClient, cachedClient, "the client of the default workspace is cached")
+
 	// this would normally run in the defer iff the test fails, but not for the purposes of testing it
 	t.Log("dumping diagnostics to filesystem")
 	output, err := cluster.DumpDiagnostics(ctx, t.Name())
@@ -257,7 +270,7 @@ func TestKongAddonDiagnosticsPostgres(t *testing.T) {
 	t.Log("checking that postgres config is present")
 	config, err := os.ReadFile(filepath.Join(output, "addons", "kong", "default_pg_config.yaml"))
 	require.NoError(t, err)
-	require.NotZero(t, len(config))
+	require.Contains(t, string(config), "diagnostics-test")
 }
 
 func TestKongWithNodePort(t *testing.T) {
@@ -0,0 +1,59 @@
+//go:build integration_tests
+
+package integration
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/addons/httpbin"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kind"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters/types/kubeconfig"
+	environment "github.com/kong/kubernetes-testing-framework/pkg/environments"
+)
+
+func TestKubeconfigCluster(t *testing.T) {
+	t.Parallel()
+
+	t.Log("deploying a kind cluster to attach to through a kubeconfig")
+	kindCluster, err := kind.NewBuilder().Build(ctx)
+	require.NoError(t, err)
+	defer func() {
+		t.Logf("cleaning up kind cluster %s", kindCluster.Name())
+		assert.NoError(t, kindCluster.Cleanup(ctx))
+	}()
+
+	t.Log("writing a kubeconfig for the kind cluster")
+	kubeconfigFile, err := clusters.TempKubeconfig(kindCluster)
+	require.NoError(t, err)
+	defer os.Remove(kubeconfigFile.Name())
+
+	t.Log("creating a cluster object from the kubeconfig")
+	cluster, err := kubeconfig.NewFromKubeconfig(kubeconfigFile.Name(), kindCluster.Name())
+	require.NoError(t, err)
+	require.Equal(t, kubeconfig.KubeconfigClusterType, cluster.Type())
+	require.Equal(t, kindCluster.Name(), cluster.Name())
+	require.Equal(t, clusters.IPv4, cluster.IPFamily())
+
+	t.Log("verifying the cluster version can be detected")
+	kindVersion, err := kindCluster.Version()
+	require.NoError(t, err)
+	version, err := cluster.Version()
+	require.NoError(t, err)
+	require.True(t, kindVersion.EQ(version))
+
+	t.Log("building an environment on top of the existing cluster")
+	env, err := environment.NewBuilder().WithExistingCluster(cluster).WithAddons(httpbin.New()).Build(ctx)
+	require.NoError(t, err)
+	require.Len(t, env.Cluster().ListAddons(), 1)
+	require.NoError(t, <-env.WaitForReady(ctx))
+
+	t.Log("verifying that cleanup does not tear down a cluster KTF does not own")
+	require.NoError(t, env.Cleanup(ctx))
+	_, err = kindCluster.Version()
+	require.NoError(t, err)
+}
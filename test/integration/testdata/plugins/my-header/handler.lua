@@ -0,0 +1,10 @@
+local MyHeaderHandler = {
+  PRIORITY = 1000,
+  VERSION = "0.1.0",
+}
+
+function MyHeaderHandler:header_filter(conf)
+  kong.response.set_header(conf.header_name, conf.header_value)
+end
+
+return MyHeaderHandler